@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	BuiltinPrefix = "builtin:"
+	InitialElo    = 1500
+	EloFactor     = 32
+)
+
+// arenaBot is a contestant of the arena, either an in-process strategy or an external command.
+type arenaBot struct {
+	Name     string
+	Strategy string
+	Command  []string
+}
+
+// botConn is a running bot that receives referee input and answers with one line per drone.
+type botConn struct {
+	input chan string
+	lines chan string
+	stop  func()
+}
+
+// arenaRating is the leaderboard entry of a bot.
+type arenaRating struct {
+	Name   string
+	Elo    float64
+	Wins   int
+	Draws  int
+	Losses int
+	Points int
+}
+
+// arenaMatchup counts the results of one pairing from the point of view of its first bot.
+type arenaMatchup struct {
+	Names  [2]string
+	Wins   int
+	Draws  int
+	Losses int
+	Scores [2]int
+}
+
+// Arena collects match results into Elo ratings and per matchup counts.
+type Arena struct {
+	Ratings  []*arenaRating
+	Matchups []*arenaMatchup
+}
+
+// runArena plays round-robin matches between the given bots and prints the leaderboard.
+func runArena(args []string) int {
+	flags := flag.NewFlagSet("arena", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: arena [flags] [name=]builtin:<strategy>|<command> ...")
+		fmt.Fprintln(flags.Output(), "Strategies:", strings.Join(StrategyNames(), ", "))
+		flags.PrintDefaults()
+	}
+	seeds := flags.Int("seeds", 10, "number of seeds each pairing plays, on both sides")
+	firstSeed := flags.Int64("seed", 1, "seed of the first game")
+	timeout := flags.Duration("timeout", time.Second, "time a bot has to answer a turn")
+	verbose := flags.Bool("v", false, "show the logs of the bots")
+	record := flags.String("record", "", "directory to save a replay of every match in")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	bots, err := parseArenaBots(flags.Args())
+	if err != nil {
+		logger.Warn(CategoryTool, err)
+		flags.Usage()
+		return 2
+	}
+	if !*verbose {
+		logWriter = io.Discard
+		defer func() { logWriter = os.Stderr }()
+	}
+
+	arena := NewArena(bots)
+	for s := 0; s < *seeds; s++ {
+		seed := *firstSeed + int64(s)
+		for i := 0; i < len(bots); i++ {
+			for j := i + 1; j < len(bots); j++ {
+				// Swap sides on the same seed to cancel any positional bias
+				for _, pair := range [][2]arenaBot{{bots[i], bots[j]}, {bots[j], bots[i]}} {
+					result, replay := playMatch(seed, pair, *timeout, *verbose)
+					arena.Record(result)
+					if *record != "" {
+						if err := SaveReplay(*record, replay); err != nil {
+							logger.Warn(CategoryTool, "Saving replay:", err)
+						}
+					}
+				}
+			}
+		}
+	}
+	arena.Print(os.Stdout)
+	return 0
+}
+
+// parseArenaBots parses bot specifications of the form [name=]builtin:<strategy> or [name=]<command>.
+func parseArenaBots(specs []string) ([]arenaBot, error) {
+	if len(specs) < 2 {
+		return nil, errors.New("arena needs at least two bots")
+	}
+	var bots []arenaBot
+	seen := map[string]int{}
+	for _, spec := range specs {
+		var bot arenaBot
+		if name, rest, ok := strings.Cut(spec, "="); ok && !strings.ContainsAny(name, " /") {
+			bot.Name, spec = name, rest
+		}
+		if strings.HasPrefix(spec, BuiltinPrefix) {
+			bot.Strategy = strings.TrimPrefix(spec, BuiltinPrefix)
+			if _, err := NewStrategy(bot.Strategy); err != nil {
+				return nil, err
+			}
+			if bot.Name == "" {
+				bot.Name = bot.Strategy
+			}
+		} else {
+			bot.Command = strings.Fields(spec)
+			if len(bot.Command) == 0 {
+				return nil, fmt.Errorf("empty bot command")
+			}
+			if bot.Name == "" {
+				bot.Name = filepath.Base(bot.Command[0])
+			}
+		}
+		seen[bot.Name]++
+		if seen[bot.Name] > 1 {
+			bot.Name = fmt.Sprintf("%s#%d", bot.Name, seen[bot.Name])
+		}
+		bots = append(bots, bot)
+	}
+	return bots, nil
+}
+
+// playMatch plays one game of the seed between two bots, the first bot being player 0, and returns
+// its result with the replay of the match.
+func playMatch(seed int64, bots [2]arenaBot, timeout time.Duration, verbose bool) (MatchResult, *Replay) {
+	referee := NewReferee(seed)
+	names := [2]string{bots[0].Name, bots[1].Name}
+	replay := NewReplay(referee)
+
+	var conns [2]*botConn
+	for p, bot := range bots {
+		conn, err := bot.start(verbose)
+		if err != nil {
+			referee.Players[p].Failure = err.Error()
+			continue
+		}
+		defer conn.close()
+		conns[p] = conn
+		conn.send(referee.InitInput(p))
+	}
+
+	for !referee.Over() {
+		var commands [2][]string
+		turn := replay.AddTurn(referee, [2]string{referee.TurnInput(0), referee.TurnInput(1)})
+		for p, conn := range conns {
+			conn.send(turn.Input[p])
+			lines, err := conn.receive(len(referee.Players[p].Drones), timeout)
+			if err != nil {
+				referee.Players[p].Failure = fmt.Sprintf("turn %d: %v", referee.Turn+1, err)
+				continue
+			}
+			commands[p] = lines
+		}
+		turn.Commands = commands
+		if referee.Over() {
+			break
+		}
+		referee.Step(commands)
+	}
+	referee.Finish()
+	replay.Finish(referee, referee.Result(seed, names))
+	return replay.Result, replay
+}
+
+// start launches the bot and connects to its input and output.
+func (bot arenaBot) start(verbose bool) (*botConn, error) {
+	if bot.Strategy != "" {
+		strategy, err := NewStrategy(bot.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		inReader, inWriter := io.Pipe()
+		outReader, outWriter := io.Pipe()
+		go func() {
+			RunBot(inReader, outWriter, strategy)
+			_ = outWriter.Close()
+		}()
+		return newBotConn(inWriter, outReader, func() {}), nil
+	}
+
+	cmd := exec.Command(bot.Command[0], bot.Command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return newBotConn(stdin, stdout, func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}), nil
+}
+
+// newBotConn pumps input to w and lines read from r in the background so a stuck bot can time out.
+func newBotConn(w io.WriteCloser, r io.Reader, stop func()) *botConn {
+	conn := &botConn{input: make(chan string, 2), lines: make(chan string, 16), stop: stop}
+	go func() {
+		for text := range conn.input {
+			if _, err := io.WriteString(w, text); err != nil {
+				break
+			}
+		}
+		_ = w.Close()
+		for range conn.input {
+		}
+	}()
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			conn.lines <- scanner.Text()
+		}
+		close(conn.lines)
+	}()
+	return conn
+}
+
+// send queues input for the bot.
+func (conn *botConn) send(text string) {
+	conn.input <- text
+}
+
+// receive waits for count lines of output from the bot.
+func (conn *botConn) receive(count int, timeout time.Duration) ([]string, error) {
+	deadline := time.After(timeout)
+	lines := make([]string, 0, count)
+	for len(lines) < count {
+		select {
+		case line, ok := <-conn.lines:
+			if !ok {
+				return nil, errors.New("bot exited")
+			}
+			lines = append(lines, line)
+		case <-deadline:
+			return nil, fmt.Errorf("timeout after %v", timeout)
+		}
+	}
+	return lines, nil
+}
+
+// close stops the bot and discards whatever it still writes.
+func (conn *botConn) close() {
+	close(conn.input)
+	conn.stop()
+	go func() {
+		for range conn.lines {
+		}
+	}()
+}
+
+// NewArena returns an arena with every bot at the initial rating.
+func NewArena(bots []arenaBot) *Arena {
+	arena := &Arena{}
+	for i, bot := range bots {
+		arena.Ratings = append(arena.Ratings, &arenaRating{Name: bot.Name, Elo: InitialElo})
+		for _, other := range bots[i+1:] {
+			arena.Matchups = append(arena.Matchups, &arenaMatchup{Names: [2]string{bot.Name, other.Name}})
+		}
+	}
+	return arena
+}
+
+// Record updates ratings and matchup counts with the result of a match.
+func (arena *Arena) Record(result MatchResult) {
+	outcome := result.Outcome()
+	first, second := arena.rating(result.Names[0]), arena.rating(result.Names[1])
+	first.record(outcome, result.Scores[0])
+	second.record(1-outcome, result.Scores[1])
+	updateElo(first, second, outcome)
+
+	for _, matchup := range arena.Matchups {
+		switch result.Names {
+		case matchup.Names:
+			matchup.record(outcome, result.Scores[0], result.Scores[1])
+		case [2]string{matchup.Names[1], matchup.Names[0]}:
+			matchup.record(1-outcome, result.Scores[1], result.Scores[0])
+		}
+	}
+}
+
+// Print writes the leaderboard sorted by rating followed by the matchup table.
+func (arena *Arena) Print(out io.Writer) {
+	ratings := append([]*arenaRating{}, arena.Ratings...)
+	sort.SliceStable(ratings, func(i, j int) bool { return ratings[i].Elo > ratings[j].Elo })
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Rank\tBot\tElo\tGames\tWins\tDraws\tLosses\tAvg score")
+	for i, rating := range ratings {
+		games := rating.Wins + rating.Draws + rating.Losses
+		fmt.Fprintf(table, "%d\t%s\t%.0f\t%d\t%d\t%d\t%d\t%.1f\n", i+1, rating.Name, rating.Elo, games,
+			rating.Wins, rating.Draws, rating.Losses, average(rating.Points, games))
+	}
+	_ = table.Flush()
+
+	fmt.Fprintln(out)
+	table = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Matchup\tWins\tDraws\tLosses\tAvg score")
+	for _, matchup := range arena.Matchups {
+		games := matchup.Wins + matchup.Draws + matchup.Losses
+		fmt.Fprintf(table, "%s vs %s\t%d\t%d\t%d\t%.1f - %.1f\n", matchup.Names[0], matchup.Names[1],
+			matchup.Wins, matchup.Draws, matchup.Losses, average(matchup.Scores[0], games), average(matchup.Scores[1], games))
+	}
+	_ = table.Flush()
+}
+
+// rating returns the leaderboard entry of the bot with the given name.
+func (arena *Arena) rating(name string) *arenaRating {
+	for _, rating := range arena.Ratings {
+		if rating.Name == name {
+			return rating
+		}
+	}
+	rating := &arenaRating{Name: name, Elo: InitialElo}
+	arena.Ratings = append(arena.Ratings, rating)
+	return rating
+}
+
+func (rating *arenaRating) record(outcome float64, score int) {
+	rating.Points += score
+	switch outcome {
+	case 1:
+		rating.Wins++
+	case 0:
+		rating.Losses++
+	default:
+		rating.Draws++
+	}
+}
+
+func (matchup *arenaMatchup) record(outcome float64, score, foeScore int) {
+	matchup.Scores[0] += score
+	matchup.Scores[1] += foeScore
+	switch outcome {
+	case 1:
+		matchup.Wins++
+	case 0:
+		matchup.Losses++
+	default:
+		matchup.Draws++
+	}
+}
+
+// updateElo moves both ratings towards the outcome of a game, 1 meaning the first bot won.
+func updateElo(first, second *arenaRating, outcome float64) {
+	expected := 1 / (1 + math.Pow(10, (second.Elo-first.Elo)/400))
+	first.Elo += EloFactor * (outcome - expected)
+	second.Elo -= EloFactor * (outcome - expected)
+}
+
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
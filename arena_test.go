@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPlayMatch_InProcessBotsFinish(t *testing.T) {
+	logWriter = io.Discard
+	bots, err := parseArenaBots([]string{"builtin:default", "builtin:idle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, replay := playMatch(1, [2]arenaBot{bots[0], bots[1]}, 5*time.Second, false)
+
+	if result.Failures[0] != "" || result.Failures[1] != "" {
+		t.Errorf("Expected no failures, got %v", result.Failures)
+	}
+	if result.Turns < 1 || result.Turns > MaxTurns {
+		t.Errorf("Expected between 1 and %d turns, got %d", MaxTurns, result.Turns)
+	}
+	if len(replay.Turns) != result.Turns || replay.Result != result {
+		t.Errorf("Expected a replay of the %d turns played, got %d", result.Turns, len(replay.Turns))
+	}
+}
+
+func TestArenaRecord_SwappedSidesUpdateSameMatchup(t *testing.T) {
+	arena := NewArena([]arenaBot{{Name: "a"}, {Name: "b"}})
+
+	arena.Record(MatchResult{Names: [2]string{"a", "b"}, Scores: [2]int{10, 5}})
+	arena.Record(MatchResult{Names: [2]string{"b", "a"}, Scores: [2]int{7, 7}})
+
+	matchup := arena.Matchups[0]
+	if matchup.Wins != 1 || matchup.Draws != 1 || matchup.Losses != 0 {
+		t.Errorf("Expected 1 win and 1 draw for a, got %+v", matchup)
+	}
+	a, b := arena.rating("a"), arena.rating("b")
+	if a.Elo <= b.Elo || a.Elo+b.Elo != 2*InitialElo {
+		t.Errorf("Expected a rated above b with a constant sum, got %.1f and %.1f", a.Elo, b.Elo)
+	}
+}
+
+func TestMatchResultOutcome_FailureLoses(t *testing.T) {
+	result := MatchResult{Scores: [2]int{30, 0}, Failures: [2]string{"timeout", ""}}
+
+	if result.Outcome() != 0 {
+		t.Errorf("Expected failing player to lose, got %v", result.Outcome())
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+const (
+	benchSeed  = 3
+	benchTurns = 40
+)
+
+// midGameState plays the default strategy against itself with the referee for a number of turns and
+// returns the state of player 0, with fish scanned and saved, monsters about and radar history built up.
+func midGameState(b *testing.B, turns int) *GameState {
+	b.Helper()
+	previous := logWriter
+	logWriter = io.Discard
+	b.Cleanup(func() { logWriter = previous })
+
+	referee := NewReferee(benchSeed)
+	var states [2]*GameState
+	for p := range states {
+		states[p] = NewGameState()
+		if err := states[p].ReadInit(strings.NewReader(referee.InitInput(p))); err != nil {
+			b.Fatal(err)
+		}
+	}
+	strategy := NewDefaultStrategy()
+	for turn := 0; turn < turns && !referee.Over(); turn++ {
+		var commands [2][]string
+		for p, state := range states {
+			var out bytes.Buffer
+			state.Out = &out
+			state.PrepareForNextTurn()
+			if err := state.ReadTurn(strings.NewReader(referee.TurnInput(p))); err != nil {
+				b.Fatal(err)
+			}
+			state.NextTurn()
+			state.EstimateAll()
+			state.TrackFoeBehavior()
+			strategy.Play(state)
+			state.MoveAll()
+			commands[p] = strings.Split(strings.TrimSpace(out.String()), "\n")
+		}
+		referee.Step(commands)
+	}
+
+	state := states[0]
+	state.Out = io.Discard
+	if fish := len(state.Creatures) - len(state.GetMonsters()); fish < 12 || len(state.GetMonsters()) < 2 {
+		b.Fatalf("Expected at least 12 fish and 2 monsters, got %d and %d", fish, len(state.GetMonsters()))
+	}
+	return state
+}
+
+func BenchmarkEstimateAll(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state.EstimateAll()
+	}
+}
+
+func BenchmarkMoveAll(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state.MoveAll()
+	}
+}
+
+func BenchmarkCalculatePotentialPoints(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state.CalculatePotentialPoints()
+		state.CalculateFoePotentialPoints()
+	}
+}
+
+func BenchmarkFindTarget(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, drone := range state.MyDrones {
+			drone.FindTarget(state)
+		}
+	}
+}
+
+func BenchmarkDroneMove(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, drone := range state.MyDrones {
+			drone.Move(state)
+		}
+	}
+}
+
+// BenchmarkTurn times everything the bot does between reading a turn and answering it.
+func BenchmarkTurn(b *testing.B) {
+	state := midGameState(b, benchTurns)
+	strategy := NewDefaultStrategy()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state.EstimateAll()
+		state.TrackFoeBehavior()
+		strategy.Play(state)
+		state.MoveAll()
+	}
+}
@@ -24,7 +24,6 @@ const (
 
 var (
 	fishDepthsByType = map[CreatureType][2]int{
-		Monster:     {5000, 10000},
 		ShallowFish: {ShallowFishMinDepth, ShallowFishMaxDepth},
 		MediumFish:  {MediumFishMinDepth, MediumFishMaxDepth},
 		DeepFish:    {DeepFishMinDepth, DeepFishMaxDepth},
@@ -35,98 +34,106 @@ type Creature struct {
 	Id              int
 	Color           int
 	Type            CreatureType
-	X               int
-	Y               int
-	Vx              int
-	Vy              int
+	Pos             Vec2
+	Speed           Vec2
 	LastVisibleTurn int
 	Dead            bool
+	Bounds          Box
+	LastSeen        Vec2
+}
+
+// Box is an axis aligned area of the map, used for the area a creature is known to be in.
+type Box struct {
+	MinX int
+	MinY int
+	MaxX int
+	MaxY int
+}
+
+// Intersect returns the overlap of both boxes, false if they do not overlap.
+func (box Box) Intersect(other Box) (Box, bool) {
+	overlap := Box{max(box.MinX, other.MinX), max(box.MinY, other.MinY), min(box.MaxX, other.MaxX), min(box.MaxY, other.MaxY)}
+	return overlap, overlap.MinX <= overlap.MaxX && overlap.MinY <= overlap.MaxY
 }
 
 // NewCreature returns a new Creature with the given ID, color and type.
 func NewCreature(id, color int, _type CreatureType) *Creature {
+	minY, maxY := creatureDepths(_type)
 	return &Creature{
 		Id:              id,
 		Color:           color,
 		Type:            _type,
 		LastVisibleTurn: -1,
+		Bounds:          Box{0, minY, MapSize, maxY},
 	}
 }
 
-// Perform creature movement based on its type and current position vx,vy and nearby creatures and drones
+// Perform creature movement along its current speed within its habitat, the area it may be in grows as
+// the prediction gets older
 func (creature *Creature) Move(state *GameState) {
-	// Get minY and maxY for the creature type
-	dimensionBoundaries := fishDepthsByType[creature.Type]
-	minY := dimensionBoundaries[0]
-	maxY := dimensionBoundaries[1]
-
-	// Add velocity to position
-	creature.X += creature.Vx
-	creature.Y += creature.Vy
-
-	// if not monster and there is fish withing 600 units, change direction
-	if creature.Type != Monster {
-		for _, fish := range state.Creatures {
-			if fish.Type == Monster {
-				continue
-			}
-			if fish.Id == creature.Id {
-				continue
-			}
-			if fish.X > creature.X-FishCollision && fish.X < creature.X+FishCollision && fish.Y > creature.Y-FishCollision && fish.Y < creature.Y+FishCollision {
-				creature.Vx = -creature.Vx
-				creature.Vy = -creature.Vy
-				break
-			}
-		}
-	}
+	minY, maxY := creatureDepths(creature.Type)
+	next, _ := CreatureMoveTo(creature.Type, creature.Pos, creature.Speed, false)
+	creature.Pos = engineRound(next)
+	creature.Bounds = creature.Bounds.Moved(creature.Speed, UncertaintyGrowth, minY, maxY)
+}
+
+// String returns a string representation of the Creature with field names.
+func (creature *Creature) String() string {
+	return fmt.Sprintf("Creature{Id: %d, Color: %d, Type: %d, Pos: %v, Speed: %v, LastVisibleTurn: %d, Dead: %t}", creature.Id, creature.Color, creature.Type, creature.Pos, creature.Speed, creature.LastVisibleTurn, creature.Dead)
+}
 
-	// Adjust for boundaries
-	if creature.X < 0 || creature.X > 10000 {
-		creature.Vx = -creature.Vx // Reverse X velocity
+// ScanProbability returns the chance that the creature is within radius of pos, assuming it is anywhere
+// within its bounds with equal probability.
+func (creature *Creature) ScanProbability(pos Vec2, radius int) float64 {
+	const samples = 5
+	bounds := creature.Bounds
+	if bounds.MaxX <= bounds.MinX && bounds.MaxY <= bounds.MinY {
+		return boolFloat(pos.Dist(creature.Pos) <= float64(radius))
 	}
 
-	if creature.Y < minY || creature.Y > maxY {
-		creature.Vy = -creature.Vy // Reverse Y velocity
+	inside := 0
+	for i := 0; i < samples; i++ {
+		for j := 0; j < samples; j++ {
+			sampleX := bounds.MinX + (bounds.MaxX-bounds.MinX)*(2*i+1)/(2*samples)
+			sampleY := bounds.MinY + (bounds.MaxY-bounds.MinY)*(2*j+1)/(2*samples)
+			if pos.Dist(V(sampleX, sampleY)) <= float64(radius) {
+				inside++
+			}
+		}
 	}
+	return float64(inside) / (samples * samples)
 }
 
-// String returns a string representation of the Creature with field names.
-func (creature *Creature) String() string {
-	return fmt.Sprintf("Creature{Id: %d, Color: %d, Type: %d, X: %d, Y: %d, Vx: %d, Vy: %d, LastVisibleTurn: %d, Dead: %t}", creature.Id, creature.Color, creature.Type, creature.X, creature.Y, creature.Vx, creature.Vy, creature.LastVisibleTurn, creature.Dead)
+// creatureIds returns the ids of the creatures, for logging
+func creatureIds(creatures []*Creature) []int {
+	ids := make([]int, 0, len(creatures))
+	for _, creature := range creatures {
+		ids = append(ids, creature.Id)
+	}
+	return ids
 }
 
 // Check if creature is scanned by any of the drones
 func (creature *Creature) IsScanned(state *GameState) bool {
-	for _, drone := range state.MyDrones {
-		for _, scan := range drone.Scans {
-			if scan.Id == creature.Id {
-				return true
-			}
-		}
-	}
-	return false
+	return Carried(state.MyDrones).Has(creature.Id)
 }
 
 // Check if creature has been delivered by any of drones
 func (creature *Creature) IsDelivered(state *GameState) bool {
-	for _, scan := range state.MyScans {
-		if scan.Id == creature.Id {
-			return true
-		}
-	}
-	return false
+	return state.index.mySaved.Has(creature.Id)
+}
+
+// IsDeliveredByFoe returns true if the foe has already saved a scan of the creature
+func (creature *Creature) IsDeliveredByFoe(state *GameState) bool {
+	return state.index.foeSaved.Has(creature.Id)
+}
+
+// IsScannedByFoe returns true if any foe drone holds an unsaved scan of the creature
+func (creature *Creature) IsScannedByFoe(state *GameState) bool {
+	return Carried(state.FoeDrones).Has(creature.Id)
 }
 
 // IsTargeted returns true if the creature is targeted by any of the drones, ignores drone thats been passed if not nil
 func (creature *Creature) IsTargeted(state *GameState, skipDrone *Drone) bool {
-	for _, drone := range state.MyDrones {
-		if skipDrone != nil && drone.Id == skipDrone.Id {
-			continue
-		}
-		if drone.Target != nil && drone.Target.Id == creature.Id {
-			return true
-		}
-	}
-	return false
+	return state.Targeted(skipDrone).Has(creature.Id)
 }
@@ -1,31 +1,28 @@
 package main
 
-import (
-	"math"
-	"math/rand"
-	"time"
-)
+import "math"
 
 const (
 	NotInitialized  = -1
 	MaxTurnsVisible = 10
 )
 
-// init initializes the random seed.
-func init() {
-	rand.Seed(time.Now().UnixNano()) // Initialize the random seed
-}
-
-// MoveAll creatures based on their type and current position vx,vy and nearby creatures and drones, only perform move if creature is visible or within max turns visible
+// MoveAll creatures based on their type and current position and speed and nearby creatures and drones, only perform move if creature is visible or within max turns visible
 func (state *GameState) MoveAll() {
+	var moved []*Creature
 	for _, creature := range state.Creatures {
 		if creature.Dead {
 			continue
 		}
 		if creature.LastVisibleTurn != NotInitialized || creature.LastVisibleTurn+MaxTurnsVisible > state.Turn {
 			creature.Move(state)
+			moved = append(moved, creature)
 		}
 	}
+	// Like the engine, speeds are updated once every creature has moved
+	for _, creature := range moved {
+		creature.Speed = creature.PredictVelocity(state)
+	}
 }
 
 // EstimateAll position of all game creatures based on drone blips, creature type and nearby creatures.
@@ -40,38 +37,65 @@ func (state *GameState) EstimateAll() {
 			fish1 := state.Creatures[i]
 			fish2 := state.Creatures[j]
 
-			if distance(fish1.X, fish1.Y, fish2.X, fish2.Y) < 500 {
+			if fish1.Pos == fish2.Pos {
+				state.SeparateSameSpot(fish1, fish2)
+			} else if fish1.Pos.Dist(fish2.Pos) < 500 {
 				state.AdjustPositions(fish1, fish2)
 			}
 		}
 	}
+
+	// Flag estimates the radar history disagrees with
+	for _, creature := range state.Creatures {
+		if creature.Dead {
+			continue
+		}
+		if contradictions := state.CheckConsistency(creature); len(contradictions) > 0 {
+			logger.Fields(LevelWarn, CategoryEstimate, "estimate contradicts radar", map[string]any{
+				"creature": creature.Id, "x": creature.Pos.X, "y": creature.Pos.Y, "blips": len(contradictions), "latest": contradictions[len(contradictions)-1].String(),
+			})
+		}
+	}
 }
 
+// AdjustPositions moves two estimated fishes too close to each other apart, a fish seen this turn is where
+// the game says it is and stays there.
 func (state *GameState) AdjustPositions(fish1, fish2 *Creature) {
 	// Calculate the midpoint between the two fishes
-	midX := (fish1.X + fish2.X) / 2
-	midY := (fish1.Y + fish2.Y) / 2
+	mid := fish1.Pos.Add(fish2.Pos).Scale(0.5)
 
 	// Move each fish away from the midpoint
-	fish1 = moveAway(fish1, midX, midY)
-	fish2 = moveAway(fish2, midX, midY)
+	if fish1.LastVisibleTurn != state.Turn {
+		fish1 = moveAway(fish1, mid)
+	}
+	if fish2.LastVisibleTurn != state.Turn {
+		fish2 = moveAway(fish2, mid)
+	}
+}
+
+// SeparateSameSpot moves apart two fishes estimated on the same spot, which have no midpoint to move away
+// from: the first one goes up and left, the second one down and right.
+func (state *GameState) SeparateSameSpot(fish1, fish2 *Creature) {
+	if fish1.LastVisibleTurn != state.Turn {
+		fish1 = moveAway(fish1, fish1.Pos.Add(V(1, 1)))
+	}
+	if fish2.LastVisibleTurn != state.Turn {
+		fish2 = moveAway(fish2, fish2.Pos)
+	}
 }
 
-func moveAway(fish *Creature, fromX, fromY int) *Creature {
-	if fish.X < fromX {
-		fish.X = max(0, fish.X-250) // move left, but not beyond 0
+func moveAway(fish *Creature, from Vec2) *Creature {
+	if fish.Pos.X < from.X {
+		fish.Pos.X = math.Max(0, fish.Pos.X-250) // move left, but not beyond 0
 	} else {
-		fish.X = min(10000, fish.X+250) // move right, but not beyond 10000
+		fish.Pos.X = math.Min(10000, fish.Pos.X+250) // move right, but not beyond 10000
 	}
 
-	if fish.Y < fromY {
-		dimensionBoundaries := fishDepthsByType[fish.Type]
-		minY, _ := dimensionBoundaries[0], dimensionBoundaries[1]
-		fish.Y = max(minY, fish.Y-250) // move up, but not beyond minY
+	minY, maxY := creatureDepths(fish.Type)
+	if fish.Pos.Y < from.Y {
+		fish.Pos.Y = math.Max(float64(minY), fish.Pos.Y-250) // move up, but not beyond minY
 	} else {
-		dimensionBoundaries := fishDepthsByType[fish.Type]
-		_, maxY := dimensionBoundaries[0], dimensionBoundaries[1]
-		fish.Y = min(maxY, fish.Y+250) // move down, but not beyond maxY
+		fish.Pos.Y = math.Min(float64(maxY), fish.Pos.Y+250) // move down, but not beyond maxY
 	}
 
 	return fish
@@ -84,9 +108,7 @@ func (state *GameState) Estimate(creature *Creature) {
 	}
 
 	// Get minY and maxY for the creature type
-	dimensionBoundaries := fishDepthsByType[creature.Type]
-	minY := dimensionBoundaries[0]
-	maxY := dimensionBoundaries[1]
+	minY, maxY := creatureDepths(creature.Type)
 
 	// Initialize possible position ranges
 	possibleXMin, possibleXMax := 0, 10000
@@ -95,61 +117,43 @@ func (state *GameState) Estimate(creature *Creature) {
 	// Adjust the possible position ranges based on each drone's radar blips
 	for _, drone := range state.MyDrones {
 		if blip, ok := drone.RadarBlips[creature.Id]; ok {
+			droneX, droneY := drone.Pos.Ints()
 			switch blip {
 			case TopRight:
-				possibleXMin = max(possibleXMin, drone.X)
-				possibleYMax = min(possibleYMax, drone.Y)
+				possibleXMin = max(possibleXMin, droneX)
+				possibleYMax = min(possibleYMax, droneY)
 			case TopLeft:
-				possibleXMax = min(possibleXMax, drone.X)
-				possibleYMax = min(possibleYMax, drone.Y)
+				possibleXMax = min(possibleXMax, droneX)
+				possibleYMax = min(possibleYMax, droneY)
 			case BottomRight:
-				possibleXMin = max(possibleXMin, drone.X)
-				possibleYMin = max(possibleYMin, drone.Y)
+				possibleXMin = max(possibleXMin, droneX)
+				possibleYMin = max(possibleYMin, droneY)
 			case BottomLeft:
-				possibleXMax = min(possibleXMax, drone.X)
-				possibleYMin = max(possibleYMin, drone.Y)
+				possibleXMax = min(possibleXMax, droneX)
+				possibleYMin = max(possibleYMin, droneY)
 			}
 		}
 	}
 
-	// Calculate the estimated position as the center of the possible range
-	creature.X = (possibleXMin + possibleXMax) / 2
-	creature.Y = (possibleYMin + possibleYMax) / 2
-
-	if creature.Vx == 0 && creature.Vy == 0 {
-		creature.Vx, creature.Vy = generateRandomVelocity()
+	creature.Bounds = Box{possibleXMin, possibleYMin, possibleXMax, possibleYMax}
 
-	}
-	// Update creature's position based on the velocity
-	newX := creature.X + creature.Vx
-	newY := creature.Y + creature.Vy
-
-	if newX < 0 || newX > 10000 {
-		creature.Vx = -creature.Vx // Reverse X velocity
-	}
-	if newY < minY || newY > maxY {
-		creature.Vy = -creature.Vy // Reverse Y velocity
-	}
-
-	// Check for collision with other fishes and adjust velocity
-	for _, otherFish := range state.Creatures {
-		if otherFish.Dead {
-			continue
-		}
-		if otherFish.Id != creature.Id && distance(newX, newY, otherFish.X, otherFish.Y) < 600 {
-			creature.Vx = -creature.Vx // Reverse X velocity
-			creature.Vy = -creature.Vy // Reverse Y velocity
-			break                      // Only need to adjust once for any collision
+	// Narrow down with the mirrored sighting of the twin when it agrees with the radar
+	if prior, ok := state.SymmetryPrior(creature); ok {
+		if overlap, ok := creature.Bounds.Intersect(prior); ok {
+			creature.Bounds = overlap
+			possibleXMin, possibleYMin, possibleXMax, possibleYMax = overlap.MinX, overlap.MinY, overlap.MaxX, overlap.MaxY
 		}
 	}
 
-	// Update position with adjusted velocity
-	creature.X += creature.Vx
-	creature.Y += creature.Vy
+	// Calculate the estimated position as the center of the possible range
+	creature.Pos = V((possibleXMin+possibleXMax)/2, (possibleYMin+possibleYMax)/2)
+
+	// Move along the predicted velocity, zero as long as the creature has never been seen
+	creature.Speed = creature.PredictVelocity(state)
+	creature.Pos = creature.Pos.Add(creature.Speed)
 
 	// Ensure the fish stays within its habitat zone
-	creature.X = clamp(creature.X, 0, 10000)
-	creature.Y = clamp(creature.Y, minY, maxY)
+	creature.Pos = Vec2{math.Max(0, math.Min(10000, creature.Pos.X)), math.Max(float64(minY), math.Min(float64(maxY), creature.Pos.Y))}
 }
 
 func min(a, b int) int {
@@ -166,13 +170,6 @@ func max(a, b int) int {
 	return b
 }
 
-func generateRandomVelocity() (int, int) {
-	angle := rand.Float64() * 2 * math.Pi // Random angle in radians
-	vx := int(200 * math.Cos(angle))
-	vy := int(200 * math.Sin(angle))
-	return vx, vy
-}
-
 func clamp(value, min, max int) int {
 	if value < min {
 		return min
@@ -13,13 +13,13 @@ func TestEstimatePosition_OneDroneBlipBL(t *testing.T) {
 	c := state.GetCreature(0)
 
 	// Check if y is within the range of the fish type
-	if c.Y < ShallowFishMinDepth || c.Y > ShallowFishMaxDepth {
-		t.Errorf("Expected y to be within the range of the fish type, got %d", c.Y)
+	if c.Pos.Y < ShallowFishMinDepth || c.Pos.Y > ShallowFishMaxDepth {
+		t.Errorf("Expected y to be within the range of the fish type, got %.0f", c.Pos.Y)
 	}
 
 	// check if estimation is withing bounds of radar blip
-	if c.X < 0 || c.X > 2500 {
-		t.Errorf("Expected x to be within the bounds of the radar blip, got %d", c.X)
+	if c.Pos.X < 0 || c.Pos.X > 2500 {
+		t.Errorf("Expected x to be within the bounds of the radar blip, got %.0f", c.Pos.X)
 	}
 }
 
@@ -34,13 +34,13 @@ func TestEstimatePosition_OneDroneBlipBR(t *testing.T) {
 	c := state.GetCreature(0)
 
 	// Check if y is within the range of the fish type
-	if c.Y < ShallowFishMinDepth || c.Y > ShallowFishMaxDepth {
-		t.Errorf("Expected y to be within the range of the fish type, got %d", c.Y)
+	if c.Pos.Y < ShallowFishMinDepth || c.Pos.Y > ShallowFishMaxDepth {
+		t.Errorf("Expected y to be within the range of the fish type, got %.0f", c.Pos.Y)
 	}
 
 	// check if estimation is withing bounds of radar blip
-	if c.X < 2500 || c.X > 10000 {
-		t.Errorf("Expected x to be within the bounds of the radar blip, got %d", c.X)
+	if c.Pos.X < 2500 || c.Pos.X > 10000 {
+		t.Errorf("Expected x to be within the bounds of the radar blip, got %.0f", c.Pos.X)
 	}
 }
 
@@ -58,13 +58,13 @@ func TestEstimatePosition_TwoDronesInBetween(t *testing.T) {
 	c := state.GetCreature(0)
 
 	// Check if y is within the range of the fish type
-	if c.Y < ShallowFishMinDepth || c.Y > ShallowFishMaxDepth {
-		t.Errorf("Expected y to be within the range of the fish type, got %d", c.Y)
+	if c.Pos.Y < ShallowFishMinDepth || c.Pos.Y > ShallowFishMaxDepth {
+		t.Errorf("Expected y to be within the range of the fish type, got %.0f", c.Pos.Y)
 	}
 
 	// check if estimation is withing bounds of radar blip
-	if c.X < 2500 || c.X > 7500 {
-		t.Errorf("Expected x to be within the bounds of the radar blip, got %d", c.X)
+	if c.Pos.X < 2500 || c.Pos.X > 7500 {
+		t.Errorf("Expected x to be within the bounds of the radar blip, got %.0f", c.Pos.X)
 	}
 }
 
@@ -82,13 +82,13 @@ func TestEstimatePosition_TwoDronesInBetweenWithOneBlip(t *testing.T) {
 	c := state.GetCreature(0)
 
 	// Check if y is within the range of the fish type
-	if c.Y < ShallowFishMinDepth || c.Y > ShallowFishMaxDepth {
-		t.Errorf("Expected y to be within the range of the fish type, got %d", c.Y)
+	if c.Pos.Y < ShallowFishMinDepth || c.Pos.Y > ShallowFishMaxDepth {
+		t.Errorf("Expected y to be within the range of the fish type, got %.0f", c.Pos.Y)
 	}
 
 	// check if estimation is withing bounds of radar blip
-	if c.X < 0 || c.X > 2500 {
-		t.Errorf("Expected x to be within the bounds of the radar blip, got %d", c.X)
+	if c.Pos.X < 0 || c.Pos.X > 2500 {
+		t.Errorf("Expected x to be within the bounds of the radar blip, got %.0f", c.Pos.X)
 	}
 }
 
@@ -112,20 +112,61 @@ func TestEstimatePosition_MultipleDronesMultipleFish(t *testing.T) {
 	for _, creature := range state.Creatures {
 
 		// Check if y is within the range of the fish type
-		if creature.Y < ShallowFishMinDepth || creature.Y > ShallowFishMaxDepth {
-			t.Errorf("Expected y to be within the range of the fish type, got %d", creature.Y)
+		if creature.Pos.Y < ShallowFishMinDepth || creature.Pos.Y > ShallowFishMaxDepth {
+			t.Errorf("Expected y to be within the range of the fish type, got %.0f", creature.Pos.Y)
 		}
 
 		// check if estimation is withing bounds of radar blip
-		if creature.X < 0 || creature.X > 2500 {
-			t.Errorf("Expected x to be within the bounds of the radar blip, got %d", creature.X)
+		if creature.Pos.X < 0 || creature.Pos.X > 2500 {
+			t.Errorf("Expected x to be within the bounds of the radar blip, got %.0f", creature.Pos.X)
 		}
 		// check if no fish withing 600 units of other
 		for _, fish := range state.Creatures {
-			if fish.Id != creature.Id && distance(creature.X, creature.Y, fish.X, fish.Y) < 500 {
-				t.Errorf("Expected no fish to be within 600 units of other fish, got %d", creature.X)
+			if fish.Id != creature.Id && creature.Pos.Dist(fish.Pos) < 500 {
+				t.Errorf("Expected no fish to be within 600 units of other fish, got %.0f", creature.Pos.X)
 			}
 		}
 	}
 
 }
+
+func TestSeparateSameSpot(t *testing.T) {
+	state := NewGameState()
+	fish1 := NewCreature(4, 0, MediumFish)
+	fish2 := NewCreature(5, 1, MediumFish)
+	fish1.Pos = V(5000, 6000)
+	fish2.Pos = V(5000, 6000)
+
+	state.SeparateSameSpot(fish1, fish2)
+
+	if fish1.Pos != V(4750, 5750) {
+		t.Errorf("Expected first fish up and left at 4750,5750, got %v", fish1.Pos)
+	}
+	if fish2.Pos != V(5250, 6250) {
+		t.Errorf("Expected second fish down and right at 5250,6250, got %v", fish2.Pos)
+	}
+}
+
+func TestEstimateAll_KeepsCreaturesSeenThisTurn(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateMyDrone(0, 3000, 2500, 0, 30)
+	state.UpdateMyDrone(1, 3400, 4000, 0, 30)
+	state.UpdateRadarBlip(0, 4, string(BottomRight))
+	state.UpdateRadarBlip(1, 4, string(TopLeft))
+	state.UpdateRadarBlip(0, 16, string(BottomRight))
+	state.UpdateRadarBlip(1, 16, string(TopLeft))
+	// A monster above the habitat of fish, next to where the fish is estimated
+	state.UpdateCreature(16, 3000, 3000, 0, 0)
+	state.NextTurn()
+
+	state.EstimateAll()
+
+	if monster := state.GetCreature(16); monster.Pos != V(3000, 3000) {
+		t.Errorf("Expected the visible monster to stay at 3000,3000, got %v", monster.Pos)
+	}
+	if fish := state.GetCreature(4); fish.Pos != V(3450, 3500) {
+		t.Errorf("Expected the estimated fish to move away to 3450,3500, got %v", fish.Pos)
+	}
+}
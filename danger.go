@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	DangerCellSize       = 250 // side of a cell of the danger grid
+	DangerHorizon        = 5   // turns ahead the grid is computed for, later turns use the last one
+	DangerMargin         = 600 // distance beyond the hit radius over which danger fades out for a known monster
+	DangerGrowth         = MonsterAttackSpeed - MonsterSpeed
+	DangerMaxUncertainty = 1000 // cap on how far a monster may be from where it is predicted
+)
+
+var dangerShades = []byte(" .:-=+*#%@")
+
+// monsterTrack is a monster as the danger field predicts it: where it is, how it moves and how far
+// from the prediction it may be.
+type monsterTrack struct {
+	Id          int
+	Pos         Vec2
+	Speed       Vec2
+	Uncertainty float64
+}
+
+// DangerField is the risk of being hit by a monster over the map for the next turns, from 0 (out of reach
+// of any monster) to 1 (within the hit radius of where a monster is predicted). Point and segment queries
+// are exact, the grid keeps the value at the center of each cell for planners and debug output and is
+// filled one turn at a time when first needed.
+type DangerField struct {
+	Turn   int
+	Size   int
+	Grid   [DangerHorizon][]float64
+	tracks []monsterTrack
+}
+
+// Danger returns the danger field of the current turn, computing it the first time it is asked for.
+func (state *GameState) Danger() *DangerField {
+	if state.danger == nil || state.danger.Turn != state.Turn {
+		state.danger = NewDangerField(state)
+	}
+	return state.danger
+}
+
+// NewDangerField predicts the monsters from their last sighting or estimate.
+func NewDangerField(state *GameState) *DangerField {
+	field := &DangerField{Turn: state.Turn, Size: (MapSize + DangerCellSize - 1) / DangerCellSize}
+	for _, monster := range state.GetMonsters() {
+		if monster.Dead {
+			continue
+		}
+		track := monsterTrack{Id: monster.Id, Pos: monster.Pos, Speed: monster.Speed}
+		if monster.LastVisibleTurn != state.Turn {
+			// Estimated monsters may be anywhere in their box
+			bounds := monster.Bounds
+			track.Uncertainty = math.Min(DangerMaxUncertainty, V(bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY).Length()/2)
+		}
+		field.tracks = append(field.tracks, track)
+	}
+	return field
+}
+
+// layer returns the grid of the given turn from now, filling it on first use.
+func (field *DangerField) layer(turn int) []float64 {
+	if field.Grid[turn-1] == nil {
+		grid := make([]float64, field.Size*field.Size)
+		for row := 0; row < field.Size; row++ {
+			for col := 0; col < field.Size; col++ {
+				grid[row*field.Size+col] = field.At(field.CellCenter(col, row), turn)
+			}
+		}
+		field.Grid[turn-1] = grid
+	}
+	return field.Grid[turn-1]
+}
+
+// falloff returns the distance beyond the hit radius over which the danger of the track fades out on the
+// given turn, the less sure its position the wider.
+func (track monsterTrack) falloff(turn int) float64 {
+	uncertainty := math.Min(DangerMaxUncertainty, track.Uncertainty+float64(DangerGrowth*(min(turn, DangerHorizon)-1)))
+	return DangerMargin + uncertainty
+}
+
+// dangerOf turns the distance to a monster into danger.
+func dangerOf(dist, falloff float64) float64 {
+	if dist <= MonsterHitRadius {
+		return 1
+	}
+	return math.Max(0, 1-(dist-MonsterHitRadius)/falloff)
+}
+
+// At returns the danger of ending the given turn from now, starting at 1, at pos.
+func (field *DangerField) At(pos Vec2, turn int) float64 {
+	danger := 0.0
+	for _, track := range field.tracks {
+		monster := track.Pos.Add(track.Speed.Scale(float64(turn)))
+		danger = math.Max(danger, dangerOf(pos.Dist(monster), track.falloff(turn)))
+	}
+	return danger
+}
+
+// Segment returns the danger of moving from one point to another on the given turn from now, taking the
+// closest approach to each monster as both move during the turn.
+func (field *DangerField) Segment(from, to Vec2, turn int) float64 {
+	danger := 0.0
+	for _, track := range field.tracks {
+		// Move the drone relative to the monster and find the closest approach
+		monster := track.Pos.Add(track.Speed.Scale(float64(turn - 1)))
+		start := from.Sub(monster)
+		end := start.Add(to.Sub(from).Sub(track.Speed))
+		dist := Vec2{}.ClosestOnSegment(start, end).Length()
+		danger = math.Max(danger, dangerOf(dist, track.falloff(turn)))
+	}
+	return danger
+}
+
+// Cell returns the danger at the center of the cell on the given turn from now.
+func (field *DangerField) Cell(col, row, turn int) float64 {
+	turn = clamp(turn, 1, DangerHorizon)
+	if col < 0 || row < 0 || col >= field.Size || row >= field.Size {
+		return 1
+	}
+	return field.layer(turn)[row*field.Size+col]
+}
+
+// CellOf returns the column and row of the cell containing pos once snapped to the grid.
+func (field *DangerField) CellOf(pos Vec2) (int, int) {
+	x, y := pos.Ints()
+	return clamp(x/DangerCellSize, 0, field.Size-1), clamp(y/DangerCellSize, 0, field.Size-1)
+}
+
+// CellCenter returns the center of the cell.
+func (field *DangerField) CellCenter(col, row int) Vec2 {
+	return V(min(col*DangerCellSize+DangerCellSize/2, MapMax), min(row*DangerCellSize+DangerCellSize/2, MapMax))
+}
+
+// Render draws the grid of the given turn from now, one character per cell from ' ' for safe to '@'
+// for within the hit radius of a monster.
+func (field *DangerField) Render(turn int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Danger on turn %d:\n", field.Turn+turn)
+	for row := 0; row < field.Size; row++ {
+		for col := 0; col < field.Size; col++ {
+			shade := int(field.Cell(col, row, turn) * float64(len(dangerShades)-1))
+			b.WriteByte(dangerShades[shade])
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
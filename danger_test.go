@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func dangerState() *GameState {
+	state := NewGameState()
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(16, 5000, 6000, 270, 0)
+	// The sighting is read before the turn it belongs to starts
+	state.Turn++
+	return state
+}
+
+func TestDangerField_FollowsPredictedMonster(t *testing.T) {
+	field := dangerState().Danger()
+
+	if danger := field.At(V(5270, 6000), 1); danger != 1 {
+		t.Errorf("Expected full danger where the monster will be, got %f", danger)
+	}
+	if danger := field.At(V(4500, 6000), 1); danger == 0 || danger == 1 {
+		t.Errorf("Expected some danger behind the monster, got %f", danger)
+	}
+	if danger := field.At(V(5000, 2000), 1); danger != 0 {
+		t.Errorf("Expected no danger far away, got %f", danger)
+	}
+	if danger := field.At(V(6200, 6000), 3); danger != 1 {
+		t.Errorf("Expected the monster to reach 6200,6000 on turn 3, got %f", danger)
+	}
+}
+
+func TestDangerField_SegmentCrossingMonster(t *testing.T) {
+	field := dangerState().Danger()
+
+	if danger := field.Segment(V(5000, 5000), V(5600, 7000), 1); danger != 1 {
+		t.Errorf("Expected crossing the monster to be dangerous, got %f", danger)
+	}
+	if danger := field.Segment(V(1000, 1000), V(1600, 1000), 1); danger != 0 {
+		t.Errorf("Expected a move far away to be safe, got %f", danger)
+	}
+}
+
+func TestDangerField_UncertaintyWidensDanger(t *testing.T) {
+	state := dangerState()
+	seen := state.Danger().At(V(5270, 7200), 1)
+
+	// The monster is now only estimated
+	state.NextTurn()
+	monster := state.GetCreature(16)
+	monster.Dead = false
+	monster.Bounds = Box{4000, 5000, 6000, 7000}
+	if estimated := state.Danger().At(V(5270, 7200), 1); estimated <= seen {
+		t.Errorf("Expected more danger around an estimated monster, got %f after %f", estimated, seen)
+	}
+}
+
+func TestDangerField_Render(t *testing.T) {
+	field := dangerState().Danger()
+	rows := strings.Split(strings.TrimRight(field.Render(1), "\n"), "\n")
+
+	if len(rows) != field.Size+1 {
+		t.Fatalf("Expected a header and %d rows, got %d", field.Size, len(rows))
+	}
+	col, row := field.CellOf(V(5270, 6000))
+	if rows[row+1][col] != '@' {
+		t.Errorf("Expected '@' at the monster, got %q", rows[row+1][col])
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TargetCandidate is a fish the drone considered as target and how it scored.
+type TargetCandidate struct {
+	Id        int     `json:"id"`
+	Value     float64 `json:"value"`
+	Turns     float64 `json:"turns"`
+	Contested bool    `json:"contested,omitempty"`
+	Score     float64 `json:"score"`
+}
+
+// MonsterDistance is a monster the drone knows about and how far it is believed to be.
+type MonsterDistance struct {
+	Id       int  `json:"id"`
+	Distance int  `json:"distance"`
+	Visible  bool `json:"visible"`
+}
+
+// Decision is the trace of how a drone chose its command for a turn, filled in by the controller as it
+// goes and logged by the strategy when it writes the command.
+type Decision struct {
+	Turn       int               `json:"turn"`
+	Drone      int               `json:"drone"`
+	Mode       string            `json:"mode"`
+	Surface    string            `json:"surface,omitempty"`
+	Candidates []TargetCandidate `json:"candidates,omitempty"`
+	Target     int               `json:"target"`
+	Monsters   []MonsterDistance `json:"monsters,omitempty"`
+	Danger     float64           `json:"danger"`
+	Avoidance  string            `json:"avoidance,omitempty"`
+	Path       []Vec2            `json:"path,omitempty"`
+	Light      *LightPlan        `json:"light,omitempty"`
+	Reason     string            `json:"reason,omitempty"`
+	Action     string            `json:"action"`
+	Message    string            `json:"message,omitempty"`
+}
+
+// NewDecision starts the trace of the drone for the current turn with the monsters it knows about,
+// closest first.
+func NewDecision(state *GameState, drone *Drone) *Decision {
+	decision := &Decision{Turn: state.Turn, Drone: drone.Id, Target: -1}
+	for _, monster := range state.GetMonsters() {
+		if monster.Dead {
+			continue
+		}
+		decision.Monsters = append(decision.Monsters, MonsterDistance{
+			Id:       monster.Id,
+			Distance: int(drone.Pos.Dist(monster.Pos)),
+			Visible:  monster.LastVisibleTurn == state.Turn,
+		})
+	}
+	sort.Slice(decision.Monsters, func(i, j int) bool {
+		return decision.Monsters[i].Distance < decision.Monsters[j].Distance
+	})
+	return decision
+}
+
+// trace returns the decision of the drone for the current turn, starting it if needed.
+func (drone *Drone) trace(state *GameState) *Decision {
+	if drone.Trace == nil || drone.Trace.Turn != state.Turn {
+		drone.Trace = NewDecision(state, drone)
+	}
+	return drone.Trace
+}
+
+// issue sets the command of the drone for the turn with its message, written by the strategy once the
+// drone is done.
+func (drone *Drone) issue(state *GameState, action, message string) {
+	decision := drone.trace(state)
+	decision.Action, decision.Message = action, message
+}
+
+// Command returns the command line of the decision, with the summary of the decision as message if
+// summarize is set.
+func (decision *Decision) Command(summarize bool) string {
+	message := decision.Message
+	if summarize {
+		message = decision.Summary()
+	}
+	return strings.TrimSpace(decision.Action + " " + message)
+}
+
+// Summary returns a short text of the decision that fits in the message of a command.
+func (decision *Decision) Summary() string {
+	parts := []string{decision.Mode}
+	if decision.Target >= 0 {
+		parts = append(parts, fmt.Sprintf("#%d", decision.Target))
+	}
+	if decision.Danger > 0 {
+		parts = append(parts, fmt.Sprintf("d%.1f", decision.Danger))
+	}
+	if decision.Avoidance != "" {
+		parts = append(parts, decision.Avoidance)
+	}
+	if decision.Light != nil && decision.Light.Light {
+		parts = append(parts, "light")
+	}
+	return strings.Join(parts, " ")
+}
+
+// String returns the whole decision on one line for the text log.
+func (decision *Decision) String() string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Drone %d %s", decision.Drone, decision.Mode)
+	if decision.Surface != "" {
+		fmt.Fprintf(&text, " (surface: %s)", decision.Surface)
+	}
+	if len(decision.Candidates) > 0 {
+		candidates := make([]string, len(decision.Candidates))
+		for i, candidate := range decision.Candidates {
+			candidates[i] = fmt.Sprintf("%d:%.2f", candidate.Id, candidate.Score)
+			if candidate.Contested {
+				candidates[i] += "*"
+			}
+		}
+		fmt.Fprintf(&text, " candidates [%s]", strings.Join(candidates, " "))
+	}
+	if decision.Target >= 0 {
+		fmt.Fprintf(&text, " target %d", decision.Target)
+	}
+	if len(decision.Monsters) > 0 {
+		monsters := make([]string, len(decision.Monsters))
+		for i, monster := range decision.Monsters {
+			monsters[i] = fmt.Sprintf("%d:%d", monster.Id, monster.Distance)
+			if !monster.Visible {
+				monsters[i] += "?"
+			}
+		}
+		fmt.Fprintf(&text, " monsters [%s]", strings.Join(monsters, " "))
+	}
+	if decision.Danger > 0 {
+		fmt.Fprintf(&text, " danger %.2f", decision.Danger)
+	}
+	if decision.Avoidance != "" {
+		fmt.Fprintf(&text, " avoid %s", decision.Avoidance)
+	}
+	if decision.Light != nil {
+		fmt.Fprintf(&text, " light %t (%s)", decision.Light.Light, decision.Light.Reason)
+	}
+	if decision.Reason != "" {
+		fmt.Fprintf(&text, " - %s", decision.Reason)
+	}
+	fmt.Fprintf(&text, " -> %s", decision.Action)
+	return text.String()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func decisionState() (*GameState, *bytes.Buffer) {
+	var out bytes.Buffer
+	state := NewGameState()
+	state.Out = &out
+	state.Turn = 5
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, MediumFish))
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(4, 3000, 3500, 0, 0)
+	state.UpdateCreature(5, 7000, 6000, 0, 0)
+	state.UpdateCreature(16, 8000, 9000, 0, 0)
+	state.UpdateMyDrone(0, 3000, 1500, 0, 30)
+	return state, &out
+}
+
+func TestDecision_TracesTargetChoice(t *testing.T) {
+	state, out := decisionState()
+	drone := state.GetDrone(0)
+
+	(&DefaultStrategy{}).Play(state)
+
+	trace := drone.Trace
+	if trace == nil || trace.Turn != 5 || trace.Mode != "target" || trace.Target != drone.Target.Id {
+		t.Fatalf("Expected a target decision for turn 5, got %v", trace)
+	}
+	if len(trace.Candidates) != 2 {
+		t.Errorf("Expected both fish as candidates, got %v", trace.Candidates)
+	}
+	if len(trace.Monsters) != 1 || trace.Monsters[0].Id != 16 || trace.Monsters[0].Distance != distance(3000, 1500, 8000, 9000) {
+		t.Errorf("Expected monster 16 with its distance, got %v", trace.Monsters)
+	}
+	if trace.Light == nil {
+		t.Errorf("Expected the light decision to be traced")
+	}
+	if line := strings.TrimSpace(out.String()); !strings.HasPrefix(line, trace.Action+" Targeting!!") {
+		t.Errorf("Expected command %q with the usual message, got %q", trace.Action, line)
+	}
+}
+
+func TestDecision_SummarizedInMessage(t *testing.T) {
+	state, out := decisionState()
+	drone := state.GetDrone(0)
+
+	(&DefaultStrategy{Summarize: true}).Play(state)
+
+	expected := drone.Trace.Action + " " + drone.Trace.Summary()
+	if line := strings.TrimSpace(out.String()); line != expected {
+		t.Errorf("Expected %q, got %q", expected, line)
+	}
+	if !strings.HasPrefix(drone.Trace.Summary(), "target #") {
+		t.Errorf("Expected the summary to name the target, got %q", drone.Trace.Summary())
+	}
+}
+
+func TestDecision_LogsOnlySummaryAtInfo(t *testing.T) {
+	buffer := captureLog(t)
+	previous := logger
+	logger = &Logger{Level: LevelInfo}
+	t.Cleanup(func() { logger = previous })
+	state, _ := decisionState()
+
+	(&DefaultStrategy{}).Play(state)
+
+	text := buffer.String()
+	if !strings.Contains(text, "summary="+state.GetDrone(0).Trace.Summary()) || strings.Contains(text, "trace=") {
+		t.Errorf("Expected only the summary of the decision at info level, got %q", text)
+	}
+}
+
+func TestDecision_StartsOverEachTurn(t *testing.T) {
+	state, _ := decisionState()
+	drone := state.GetDrone(0)
+	drone.trace(state).Reason = "old"
+
+	state.Turn++
+	if trace := drone.trace(state); trace.Reason != "" || trace.Turn != state.Turn {
+		t.Errorf("Expected a fresh decision on a new turn, got %v", trace)
+	}
+}
+
+func TestNewDefaultStrategy_SummarizesAsConfigured(t *testing.T) {
+	captureLog(t)
+	previous := logger
+	t.Cleanup(func() { logger = previous })
+
+	for _, summarize := range []bool{false, true} {
+		spec := "info"
+		if summarize {
+			spec += ",summarize"
+		}
+		configured, err := ParseLogger(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger = configured
+		state, out := decisionState()
+
+		NewDefaultStrategy().Play(state)
+
+		trace := state.GetDrone(0).Trace
+		message := "Targeting!!"
+		if summarize {
+			message = trace.Summary()
+		}
+		if line := strings.TrimSpace(out.String()); !strings.HasPrefix(line, trace.Action+" "+message) {
+			t.Errorf("Expected %q to write %q with message %q, got %q", spec, trace.Action, message, line)
+		}
+	}
+}
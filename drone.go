@@ -3,17 +3,22 @@ package main
 import "fmt"
 
 type Drone struct {
-	Id                   int
-	X                    int
-	Y                    int
-	PrevTargetDirectionX int
-	PrevTargetDirectionY int
-	Emergency            int
-	Battery              int
-	Scans                []*Creature
-	RadarBlips           map[int]RadarBlip
-	Target               *Creature
-	LastLightTurn        int
+	Id                  int
+	Pos                 Vec2
+	PrevTargetDirection Vec2
+	Emergency           int
+	Battery             int
+	Scans               []*Creature
+	RadarBlips          map[int]RadarBlip
+	Target              *Creature
+	LastLightTurn       int
+	Next                *Vec2 // where the command of this turn takes the drone, nil until it is given
+	History             []Vec2
+	PrevBattery         int
+	PrevScans           []*Creature
+	Behavior            []FoeTurnRecord
+	Trace               *Decision
+	scanned             IdSet
 }
 
 type RadarBlip string
@@ -25,25 +30,48 @@ const (
 	BottomLeft  RadarBlip = "BL"
 )
 
-// AddScan to the drone's Scans slice if not present already.
+// AddScan to the drone's Scans slice if not present already, drones in emergency hold no scans.
 func (drone *Drone) AddScan(creature *Creature) {
 	if drone.Scans == nil {
 		drone.Scans = []*Creature{}
 	}
-	if creature == nil {
+	if creature == nil || drone.IsEmergency() {
 		return
 	}
-	for _, scan := range drone.Scans {
-		if scan.Id == creature.Id {
-			return
-		}
+	if drone.scanned.Has(creature.Id) {
+		return
 	}
 	drone.Scans = append(drone.Scans, creature)
+	drone.scanned = drone.scanned.Add(creature.Id)
+}
+
+// UpdateEmergency sets the emergency state of the drone, a drone hit by a monster loses its unsaved
+// scans and releases its target so the other drone can pick it up.
+func (drone *Drone) UpdateEmergency(emergency int) {
+	drone.Emergency = emergency
+	if drone.IsEmergency() {
+		drone.ClearScans()
+		drone.Target = nil
+	}
+}
+
+// IsEmergency returns true if the drone was hit by a monster and is floating back to the surface.
+func (drone *Drone) IsEmergency() bool {
+	return drone.Emergency == 1
+}
+
+// TurnsUntilRepaired returns the turns a drone in emergency needs to float back to the surface.
+func (drone *Drone) TurnsUntilRepaired() int {
+	if !drone.IsEmergency() {
+		return 0
+	}
+	return (max(0, int(drone.Pos.Y)-SurfaceDepth) + DroneEmergencySpeed - 1) / DroneEmergencySpeed
 }
 
 // ClearScans clears the drone's Scans slice.
 func (drone *Drone) ClearScans() {
 	drone.Scans = []*Creature{}
+	drone.scanned = 0
 }
 
 // AddRadarBlip to the drone's RadarBlips map if not present already.
@@ -58,7 +86,7 @@ func (drone *Drone) AddRadarBlip(creatureId int, radar RadarBlip) {
 
 // String returns a string representation of the Drone with field names.
 func (drone *Drone) String() string {
-	return fmt.Sprintf("Drone{Id: %d, X: %d, Y: %d, Emergency: %d, Battery: %d, Scans: %v, RadarBlips: %v}", drone.Id, drone.X, drone.Y, drone.Emergency, drone.Battery, drone.Scans, drone.RadarBlips)
+	return fmt.Sprintf("Drone{Id: %d, Pos: %v, Emergency: %d, Battery: %d, Scans: %v, RadarBlips: %v}", drone.Id, drone.Pos, drone.Emergency, drone.Battery, drone.Scans, drone.RadarBlips)
 }
 
 // ClearRadarBlips clears the drone's RadarBlips map.
@@ -5,26 +5,42 @@ import (
 	"math"
 )
 
-const (
-	MinLightDepth      = 2500
-	MinBatteryLevel    = 5
-	DroneMovement      = 600
-	MonsterMinDistance = 1500
-)
+const DroneMovement = 600
 
 // Move moves drone to target if monster is in way tries to avoid it
 func (drone *Drone) Move(state *GameState) {
+	trace := drone.trace(state)
+	if drone.IsEmergency() {
+		trace.Mode = "repair"
+		drone.WaitForRepair(state)
+		return
+	}
 
-	points := state.CalculatePotentialPoints()
-	Log("Points", points)
-	if points > 63 {
+	plan := state.PlanSurfacing(drone)
+	surface, reason := drone.ShouldSurface(state, plan)
+	logger.Debug(CategoryScore, "Drone", drone.Id, "surfacing takes", plan.Turns, "turns, saving", creatureIds(plan.Scans), "on turn", plan.ArrivalTurn, "-", reason)
+	trace.Surface = reason
+	if surface {
+		trace.Mode = "surface"
 		drone.Ascend(state)
 		return
 	}
 
+	// Once the foe cannot catch up anymore, scare away the fish it still needs
+	if state.IsScoreLocked() {
+		if target := drone.FindDenialTarget(state); target != nil {
+			trace.Mode, trace.Target = "deny", target.Id
+			drone.Target = target
+			drone.MoveTo(state, drone.GetNextPositionTowardsTarget(drone.DenialPoint(target)))
+			return
+		}
+	}
+
 	if drone.Target != nil {
 		if drone.Target.IsScanned(state) || drone.Target.IsDelivered(state) || drone.Target.IsTargeted(state, drone) {
 			drone.Target = nil
+		} else if state.IsEndgame() && !drone.CanDeliverInTime(state, drone.Target) {
+			drone.Target = nil
 		}
 	}
 	if drone.Target == nil {
@@ -33,33 +49,54 @@ func (drone *Drone) Move(state *GameState) {
 
 	// If no target found, ascend to surface
 	if drone.Target == nil {
+		trace.Mode, trace.Reason = "ascend", "no target"
 		drone.Ascend(state)
 		return
 	}
 
+	trace.Mode, trace.Target = "target", drone.Target.Id
 	drone.MoveToTarget(state)
 }
 
-// Ascend function for drone to ascend to surface
+// Ascend function for drone to ascend to surface along the planned safe path
 func (drone *Drone) Ascend(state *GameState) {
-	command := fmt.Sprintf("MOVE %d %d %d ASCENDIIING!", drone.X, 500, drone.GetLightPower(state))
-	fmt.Println(command)
+	target := Vec2{drone.Pos.X, SurfaceDepth}
+	if plan := state.PlanSurfacing(drone); len(plan.Path) > 0 {
+		target = plan.Path[0]
+		drone.trace(state).Path = plan.Path
+	}
+	drone.moveEnd(target)
+	x, y := target.Ints()
+	drone.issue(state, fmt.Sprintf("MOVE %d %d %d", x, y, drone.GetLightPower(state, target)), "ASCENDIIING!")
 }
 
 // Wait function for drone to wait
 func (drone *Drone) Wait(state *GameState) {
-	command := fmt.Sprintf("WAIT %d", drone.GetLightPower(state))
-	fmt.Println(command)
+	end := engineRound(DroneWaitAt(drone.Pos))
+	drone.Next = &end
+	drone.issue(state, fmt.Sprintf("WAIT %d", drone.GetLightPower(state, end)), "")
 }
 
-// MoveTo function for drone to move to x,y
-func (drone *Drone) MoveTo(state *GameState, x, y int) {
+// WaitForRepair sends a harmless command for a drone in emergency, its command is ignored until repaired
+func (drone *Drone) WaitForRepair(state *GameState) {
+	drone.issue(state, "WAIT 0", fmt.Sprintf("Emergency, back on turn %d", state.Turn+drone.TurnsUntilRepaired()))
+}
+
+// MoveTo function for drone to move to target
+func (drone *Drone) MoveTo(state *GameState, target Vec2) {
 	message := "Suurface"
 	if drone.Target != nil {
 		message = fmt.Sprintf("Target: %d", drone.Target.Id)
 	}
-	command := fmt.Sprintf("MOVE %d %d %d Targeting!! %s", x, y, drone.GetLightPower(state), message)
-	fmt.Println(command)
+	drone.moveEnd(target)
+	x, y := target.Ints()
+	drone.issue(state, fmt.Sprintf("MOVE %d %d %d", x, y, drone.GetLightPower(state, target)), "Targeting!! "+message)
+}
+
+// moveEnd records where a move towards target takes the drone this turn
+func (drone *Drone) moveEnd(target Vec2) {
+	end := drone.GetNextPositionTowardsTarget(target)
+	drone.Next = &end
 }
 
 // MoveToTarget moves drone to target
@@ -69,151 +106,105 @@ func (drone *Drone) MoveToTarget(state *GameState) {
 		return
 	}
 
-	monsterInPath := drone.GetMonstersInPath(state, drone.Target.X, drone.Target.Y)
-	targetX, targetY := drone.GetNextPositionTowardsTarget(drone.Target.X, drone.Target.Y)
-	if len(monsterInPath) > 0 {
-		Log("Monster in path", monsterInPath)
-		targetX, targetY = drone.CalculateBestPathToAvoidMonsters(state, targetX, targetY)
-	}
-
-	drone.MoveTo(state, targetX, targetY)
-
-}
-
-func (drone *Drone) GetNextPositionTowardsTarget(targetX, targetY int) (int, int) {
-	// Calculate the difference in x and y coordinates between the drone and the target
-	diffX := targetX - drone.X
-	diffY := targetY - drone.Y
-
-	// Calculate the distance to the target
-	distanceToTarget := math.Sqrt(float64(diffX*diffX + diffY*diffY))
-
-	// If the distance is less than or equal to the drone's movement, the drone can reach the target in one move
-	if distanceToTarget <= DroneMovement {
-		return targetX, targetY
+	target := drone.ChooseEndPoint(state)
+	approach := drone.ApproachPoint(drone.Target)
+	if danger := state.Danger().Segment(drone.Pos, target, 1); danger > 0 {
+		logger.Debug(CategoryAvoid, "Drone", drone.Id, "danger", danger, "on the way to", target)
+		drone.trace(state).Danger = danger
+		target = drone.AvoidMonsters(state, target, approach)
+	} else {
+		if wait, reason := drone.ShouldWait(state, target, approach); wait {
+			logger.Debug(CategoryTarget, "Drone", drone.Id, "waits -", reason)
+			drone.trace(state).Reason = reason
+			drone.Wait(state)
+			return
+		}
 	}
 
-	// Normalize the difference vector
-	normX, normY := float64(diffX)/distanceToTarget, float64(diffY)/distanceToTarget
+	drone.MoveTo(state, target)
 
-	// Scale the normalized vector to the drone's movement distance
-	nextX := drone.X + int(normX*DroneMovement)
-	nextY := drone.Y + int(normY*DroneMovement)
-
-	return nextX, nextY
 }
 
-func (drone *Drone) CalculateBestPathToAvoidMonsters(state *GameState, targetX, targetY int) (int, int) {
-	// Define angles to check for alternative paths
-	angles := []int{-90, -45, 0, 45, 90}
-	bestX, bestY := drone.X, drone.Y
-	maxDistanceFromMonsters := 0
-
-	for _, angle := range angles {
-		// Calculate new direction with the given angle
-		newVx, newVy := rotateVectorTowards(targetX-drone.X, targetY-drone.Y, angle)
-		newX, newY := drone.X+newVx, drone.Y+newVy
-
-		// Ensure the drone stays within bounds
-		newX = clamp(newX, 0, 10000)
-		newY = clamp(newY, 0, 10000)
-
-		// Calculate the minimum distance to any monster from this new position
-		minDist := drone.MinDistanceToAnyMonster(state, newX, newY)
-
-		// Choose the direction that maximizes the distance to the nearest monster
-		if minDist > maxDistanceFromMonsters {
-			bestX, bestY = newX, newY
-			maxDistanceFromMonsters = minDist
-		}
-	}
-
-	return bestX, bestY
+// GetNextPositionTowardsTarget returns where the drone ends this turn moving towards target, the target
+// itself if it is within one move
+func (drone *Drone) GetNextPositionTowardsTarget(target Vec2) Vec2 {
+	return engineRound(DroneMoveTo(drone.Pos, target))
 }
 
-func (drone *Drone) GetMonstersInPath(state *GameState, targetX, targetY int) []*Creature {
-	var monstersInPath []*Creature
-	monsters := state.GetMonsters()
-
-	// Calculate path increments
-	diffX := float64(targetX - drone.X)
-	diffY := float64(targetY - drone.Y)
-	distanceToTarget := math.Sqrt(diffX*diffX + diffY*diffY)
-	steps := int(distanceToTarget / DroneMovement)
-	if steps == 0 {
-		steps = 1
-	}
-
-	// Check each step along the path
-	for i := 0; i <= steps; i++ {
-		pointX := drone.X + int(float64(i)*diffX/float64(steps))
-		pointY := drone.Y + int(float64(i)*diffY/float64(steps))
-
-		for _, monster := range monsters {
-			if distance(pointX, pointY, monster.X, monster.Y) <= 600 {
-				monstersInPath = appendUniqueMonster(monstersInPath, monster)
-			}
-		}
+// AvoidMonsters returns where to end the turn on a safe route to the approach point, re-planned every
+// turn through the danger field, falling back to turning away from the monsters when the planned first
+// move is not safer or there is no route
+func (drone *Drone) AvoidMonsters(state *GameState, end, approach Vec2) Vec2 {
+	fallback := drone.CalculateBestPathToAvoidMonsters(state, end)
+	path, ok := drone.PlanPath(state, approach)
+	if !ok {
+		logger.Warn(CategoryAvoid, "Drone", drone.Id, "has no safe path")
+		return fallback
 	}
-
-	return monstersInPath
-}
-
-// Helper function to add unique monsters to the slice
-func appendUniqueMonster(monsters []*Creature, monster *Creature) []*Creature {
-	for _, m := range monsters {
-		if m.Id == monster.Id {
-			return monsters
-		}
+	logger.Debug(CategoryAvoid, "Drone", drone.Id, path)
+	first := path.Points[0]
+	danger := state.Danger()
+	if danger.Segment(drone.Pos, first, 1) > danger.Segment(drone.Pos, fallback, 1) {
+		return fallback
 	}
-	return append(monsters, monster)
+	trace := drone.trace(state)
+	trace.Avoidance, trace.Path = fmt.Sprintf("path %dt", path.Turns), path.Points
+	return first
 }
 
-func (drone *Drone) MinDistanceToAnyMonster(state *GameState, x, y int) int {
-	minDistance := math.MaxInt32
-	for _, monster := range state.GetMonsters() {
-		dist := distance(x, y, monster.X, monster.Y)
-		if dist < minDistance {
-			minDistance = dist
-		}
-	}
-	return minDistance
-}
+// CalculateBestPathToAvoidMonsters tries moves turned away from target, the least turned first, and
+// returns the one with the lowest danger
+func (drone *Drone) CalculateBestPathToAvoidMonsters(state *GameState, target Vec2) Vec2 {
+	// Define angles to check for alternative paths
+	angles := []float64{0, -45, 45, -90, 90}
+	best := drone.Pos
+	bestAngle, bestDanger := 0.0, math.Inf(1)
 
-func (drone *Drone) FindNearestMonster(state *GameState, radius int) (*Creature, int) {
-	var nearestMonster *Creature
-	nearestDistance := radius + 1 // Initialize with a value larger than the search radius
+	direction := target.Sub(drone.Pos)
+	for _, angle := range angles {
+		// Calculate new direction with the given angle, staying within bounds
+		next := engineRound(drone.Pos.Add(direction.Rotate(angle).WithLength(DroneMovement)).ClampToMap())
 
-	for _, creature := range state.Creatures {
-		if creature.Type == Monster {
-			dist := distance(drone.X, drone.Y, creature.X, creature.Y)
-			if dist < nearestDistance {
-				nearestDistance = dist
-				nearestMonster = creature
-			}
+		// Choose the direction least likely to get the drone hit
+		if danger := state.Danger().Segment(drone.Pos, next, 1); danger < bestDanger {
+			best, bestAngle, bestDanger = next, angle, danger
 		}
 	}
 
-	return nearestMonster, nearestDistance
+	drone.trace(state).Avoidance = fmt.Sprintf("angle %.0f", bestAngle)
+	return best
 }
 
-// FindTarget Finds best target to move to, prefers deeper fish to shallow and closest to the drone while check if there are no monsters within the target path and radius of 2000
+// FindTarget Finds best target to move to, the fish with the highest marginal value including its share of
+// color and type set bonuses for each turn needed to reach it
 func (drone *Drone) FindTarget(state *GameState) *Creature {
 	var bestTarget *Creature
-	var highestDepth = -1
-	var bestTargetDistance = math.MaxInt32
+	var bestScore = 0.0
 
+	trace := drone.trace(state)
+	trace.Candidates = trace.Candidates[:0]
 	for _, creature := range state.Creatures {
-		if creature.Dead || creature.IsScanned(state) || creature.IsDelivered(state) || creature.IsTargeted(state, drone) {
+		if creature.Type == Monster || creature.Dead || creature.IsScanned(state) || creature.IsDelivered(state) || creature.IsTargeted(state, drone) {
+			continue
+		}
+		if state.IsEndgame() && !drone.CanDeliverInTime(state, creature) {
 			continue
 		}
 
-		creatureDepth := int(creature.Type)
-		distanceToCreature := distance(drone.X, drone.Y, creature.X, creature.Y)
+		turnsToCreature := 1 + drone.Pos.Dist(creature.Pos)/DroneMovement
+		value := state.MarginalValue(creature)
+		score := value / turnsToCreature
+		// The foe getting there first likely takes the first save bonus
+		contested := state.FoeWillScan(creature, int(turnsToCreature))
+		if contested {
+			score *= FoeContestedPenalty
+		}
+		trace.Candidates = append(trace.Candidates, TargetCandidate{
+			Id: creature.Id, Value: value, Turns: turnsToCreature, Contested: contested, Score: score,
+		})
 
-		if creatureDepth > highestDepth || (creatureDepth == highestDepth && distanceToCreature < bestTargetDistance) {
-			highestDepth = creatureDepth
-			bestTargetDistance = distanceToCreature
+		if score > bestScore {
+			bestScore = score
 			bestTarget = creature
 		}
 	}
@@ -221,60 +212,15 @@ func (drone *Drone) FindTarget(state *GameState) *Creature {
 	return bestTarget
 }
 
-// GetLightPower returns  1 if light is to be allowed or 0 if not,
-// Light can be used if drone is below 2500 units from the surface energy is more than 5 and at least 2 turns have passed
-func (drone *Drone) GetLightPower(state *GameState) int {
-	if drone.Y > MinLightDepth && drone.Battery > MinBatteryLevel && drone.LastLightTurn+2 < state.Turn && !drone.IsMonstersNearby(state) {
+// GetLightPower returns 1 if light is to be used when moving towards target or 0 if not,
+// the light planner weighs the fish expected within the lit radius against battery and monster risk
+func (drone *Drone) GetLightPower(state *GameState, target Vec2) int {
+	plan := drone.PlanLight(state, drone.GetNextPositionTowardsTarget(target))
+	logger.Debug(CategoryLight, "Drone", drone.Id, plan)
+	drone.trace(state).Light = &plan
+	if plan.Light {
 		drone.LastLightTurn = state.Turn
 		return 1
 	}
 	return 0
 }
-
-// GetNextPoint calcualtes next x,y point of drone to move to target, movement is 600 units
-func (drone *Drone) GetNextPoint(state *GameState) (int, int) {
-	if drone.Target == nil {
-		Log("No target available")
-		return drone.X, drone.Y
-	}
-
-	// Calculate the difference in position using float64 for precision
-	diffX := float64(drone.Target.X - drone.X)
-	diffY := float64(drone.Target.Y - drone.Y)
-
-	// Calculate the distance to the target
-	distanceToTarget := math.Sqrt(diffX*diffX + diffY*diffY)
-
-	// Normalize the difference
-	normX, normY := diffX/distanceToTarget, diffY/distanceToTarget
-
-	// Calculate move distance, which is the minimum of the distance to the target or the drone's movement range
-	moveDistance := math.Min(distanceToTarget, float64(DroneMovement))
-
-	// Calculate the next position and convert it back to int
-	nextX := drone.X + int(normX*moveDistance)
-	nextY := drone.Y + int(normY*moveDistance)
-
-	Log("Moving towards target:", drone.Target.Id, "Next position:", nextX, nextY)
-	return nextX, nextY
-}
-
-// IsMonstersNearby returns if at least 1 monster is within 1000 units of drone
-func (drone *Drone) IsMonstersNearby(state *GameState) bool {
-	for _, creature := range state.Creatures {
-		if creature.Type == Monster && distance(drone.X, drone.Y, creature.X, creature.Y) < MonsterMinDistance {
-			return true
-		}
-	}
-	return false
-}
-
-func (drone *Drone) GetNearbyMonsters(state *GameState, radius int) []*Creature {
-	var nearbyMonsters []*Creature
-	for _, creature := range state.Creatures {
-		if creature.Type == Monster && distance(drone.X, drone.Y, creature.X, creature.Y) < radius {
-			nearbyMonsters = append(nearbyMonsters, creature)
-		}
-	}
-	return nearbyMonsters
-}
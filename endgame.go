@@ -0,0 +1,164 @@
+package main
+
+import "math"
+
+const (
+	EndgameTurns  = 20 // turns left from which there is no time for another full dive
+	EndgameMargin = 2  // turns kept in hand when delivering before the turn limit
+)
+
+// TurnsRemaining returns the number of turns left to play after the current one.
+func (state *GameState) TurnsRemaining() int {
+	return max(0, MaxTurns-state.Turn)
+}
+
+// IsEndgame returns true when the game ends before another full dive can be made.
+func (state *GameState) IsEndgame() bool {
+	return state.TurnsRemaining() <= EndgameTurns
+}
+
+// MaxPossibleFoeScore returns the best score the foe can still reach in the projection of ScoreRace.
+func (state *GameState) MaxPossibleFoeScore() int {
+	_, foeScore := state.ScoreRace()
+	return foeScore
+}
+
+// ScoreRace projects both end of game scores from one race to the surface: each side saves the scans it
+// holds when its drone gets there along its surfacing plan, at the latest at the end of the game, and the
+// foe also saves every fish still in the game it can reach in time, as early as it could. Fish and sets
+// are first for the side saving them no later than the other.
+func (state *GameState) ScoreRace() (int, int) {
+	mySaves := state.saveTurns(state.MyDrones, state.MyScans)
+	foeSaves := state.saveTurns(state.FoeDrones, state.FoeScans)
+	for _, creature := range state.Creatures {
+		if _, ok := foeSaves[creature.Id]; ok || creature.Type == Monster || creature.Dead {
+			continue
+		}
+		if turn, ok := state.earliestSave(state.FoeDrones, creature); ok {
+			foeSaves[creature.Id] = turn
+		}
+	}
+	return state.raceScore(state.MyScore, mySaves, foeSaves), state.raceScore(state.FoeScore, foeSaves, mySaves)
+}
+
+// savedBefore is the save turn of the fish a side has already saved.
+const savedBefore = -1
+
+// saveTurns returns the turn each fish saved or held by the drones is saved on: when the first drone
+// holding it reaches the surface, drones in emergency lost their scans.
+func (state *GameState) saveTurns(drones []*Drone, saved []*Creature) map[int]int {
+	turns := make(map[int]int)
+	for _, creature := range saved {
+		turns[creature.Id] = savedBefore
+	}
+	for _, drone := range drones {
+		if drone.IsEmergency() || len(drone.Scans) == 0 {
+			continue
+		}
+		arrival := min(state.PlanSurfacing(drone).ArrivalTurn, MaxTurns)
+		for _, scan := range drone.Scans {
+			if turn, ok := turns[scan.Id]; !ok || arrival < turn {
+				turns[scan.Id] = arrival
+			}
+		}
+	}
+	return turns
+}
+
+// earliestSave returns the first turn one of the drones can have the creature saved, going straight to
+// its scan radius then up, and false if none can scan it before the game ends.
+func (state *GameState) earliestSave(drones []*Drone, creature *Creature) (int, bool) {
+	earliest, found := MaxTurns, false
+	for _, drone := range drones {
+		scanTurns, surfaceTurns := drone.TurnsToSave(creature)
+		if state.Turn+scanTurns > MaxTurns {
+			continue
+		}
+		earliest, found = min(earliest, state.Turn+scanTurns+surfaceTurns), true
+	}
+	return earliest, found
+}
+
+// TurnsToSave returns the turns the drone needs to get the creature within its scan radius, once repaired,
+// and then to bring the scan up from the depth of the creature to the surface.
+func (drone *Drone) TurnsToSave(creature *Creature) (int, int) {
+	reach := math.Max(0, drone.Pos.Dist(creature.Pos)-ScanRadius)
+	scanTurns := drone.TurnsUntilRepaired() + int(math.Ceil(reach/DroneMovement))
+	surfaceTurns := int(math.Ceil(math.Max(0, creature.Pos.Y-SurfaceDepth) / DroneMovement))
+	return scanTurns, surfaceTurns
+}
+
+// raceScore adds to the score of a side the fish and sets it saves in the race and has not saved yet,
+// doubled when the other side does not save them earlier.
+func (state *GameState) raceScore(score int, saves, otherSaves map[int]int) int {
+	for id, turn := range saves {
+		if turn == savedBefore {
+			continue
+		}
+		other, ok := otherSaves[id]
+		score += getScanPoints(state.GetCreature(id).Type, !ok || other >= turn)
+	}
+	for _, set := range state.CreatureSets() {
+		turn, complete := setSaveTurn(set, saves)
+		if !complete || turn == savedBefore {
+			continue
+		}
+		points := set.Points
+		if other, ok := setSaveTurn(set, otherSaves); !ok || other >= turn {
+			points *= 2
+		}
+		score += points
+	}
+	return score
+}
+
+// setSaveTurn returns the turn the last fish of the set is saved on, false if one of them is not saved.
+func setSaveTurn(set CreatureSet, saves map[int]int) (int, bool) {
+	last := savedBefore
+	for _, member := range set.Members {
+		turn, ok := saves[member.Id]
+		if !ok {
+			return 0, false
+		}
+		last = max(last, turn)
+	}
+	return last, true
+}
+
+// IsScoreLocked returns true if the foe cannot catch up with my end of game score anymore.
+func (state *GameState) IsScoreLocked() bool {
+	myScore, foeScore := state.ScoreRace()
+	return myScore > foeScore
+}
+
+// CanDeliverInTime returns true if the drone can reach the creature and bring its scan to the surface
+// before the game ends.
+func (drone *Drone) CanDeliverInTime(state *GameState, creature *Creature) bool {
+	scanTurns, surfaceTurns := drone.TurnsToSave(creature)
+	return scanTurns+surfaceTurns+EndgameMargin < state.TurnsRemaining()
+}
+
+// FindDenialTarget returns the fish the foe still needs that is closest to leaving the map, so the drone
+// can scare it away once the score is locked.
+func (drone *Drone) FindDenialTarget(state *GameState) *Creature {
+	var bestTarget *Creature
+	bestEdgeDistance := float64(MapSize)
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster || creature.Dead || creature.IsDeliveredByFoe(state) || creature.IsScannedByFoe(state) || creature.IsTargeted(state, drone) {
+			continue
+		}
+		if edgeDistance := math.Min(creature.Pos.X, MapSize-creature.Pos.X); edgeDistance < bestEdgeDistance {
+			bestTarget, bestEdgeDistance = creature, edgeDistance
+		}
+	}
+	return bestTarget
+}
+
+// DenialPoint returns where to place the drone so the fish flees towards the closest map edge.
+func (drone *Drone) DenialPoint(creature *Creature) Vec2 {
+	offset := V(FishHearingRadius/2, 0)
+	if creature.Pos.X < MapSize/2 {
+		return creature.Pos.Add(offset).ClampToMap()
+	}
+	return creature.Pos.Sub(offset).ClampToMap()
+}
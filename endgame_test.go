@@ -0,0 +1,101 @@
+package main
+
+import "testing"
+
+func TestIsScoreLocked(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, DeepFish))
+	state.UpdateFoeDrone(1, 5000, 500, 0, 30)
+
+	// Foe can still get 2 deep fish first, both colors and the deep type: 12 + 12 + 8
+	if max := state.MaxPossibleFoeScore(); max != 32 {
+		t.Errorf("Expected foe to be able to reach 32 points, got %d", max)
+	}
+
+	state.MyScore = 32
+	if state.IsScoreLocked() {
+		t.Errorf("Expected score not to be locked on a possible draw")
+	}
+
+	state.MyScore = 33
+	if !state.IsScoreLocked() {
+		t.Errorf("Expected score to be locked")
+	}
+}
+
+func TestScoreRace_FirstToSaveTakesTheBonus(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, DeepFish))
+	state.UpdateCreature(5, 5000, 8000, 0, 0)
+	state.UpdateMyDrone(0, 5000, 1000, 0, 30)
+	state.UpdateFoeDrone(1, 1000, 500, 0, 30)
+	state.GetDrone(0).AddScan(state.GetCreature(5))
+	// The foe saved fish 4 and its color first
+	state.FoeScans = append(state.FoeScans, state.GetCreature(4))
+	state.FoeScore = 12
+
+	myScore, foeScore := state.ScoreRace()
+
+	// I save fish 5 and its color next turn, first: 6 + 6
+	if myScore != 12 {
+		t.Errorf("Expected my projected score to be 12, got %d", myScore)
+	}
+	// The foe gets fish 5 and its color after me, only the deep type first: 12 + 3 + 3 + 8
+	if foeScore != 26 {
+		t.Errorf("Expected the foe to reach at most 26, got %d", foeScore)
+	}
+}
+
+func TestFindTarget_EndgameSkipsFishThatCannotBeDelivered(t *testing.T) {
+	state := NewGameState()
+	state.Turn = MaxTurns - 10
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.UpdateCreature(4, 5000, 9500, 0, 0)
+	state.UpdateCreature(5, 5000, 2800, 0, 0)
+	state.UpdateMyDrone(0, 5000, 1500, 0, 30)
+
+	target := state.GetDrone(0).FindTarget(state)
+
+	if target == nil || target.Id != 5 {
+		t.Errorf("Expected the shallow fish as target, got %v", target)
+	}
+}
+
+func TestFindDenialTarget_ClosestToEdge(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, MediumFish))
+	state.AddCreature(NewCreature(5, 1, MediumFish))
+	state.UpdateCreature(4, 4000, 6000, 0, 0)
+	state.UpdateCreature(5, 9200, 6000, 0, 0)
+	state.UpdateMyDrone(0, 5000, 6000, 0, 30)
+	drone := state.GetDrone(0)
+
+	target := drone.FindDenialTarget(state)
+
+	if target == nil || target.Id != 5 {
+		t.Fatalf("Expected fish 5 close to the edge, got %v", target)
+	}
+	if point := drone.DenialPoint(target); point.X >= target.Pos.X {
+		t.Errorf("Expected drone to push the fish right from x %.0f", point.X)
+	}
+}
+
+func TestCanDeliverInTime_AgreesWithEarliestSave(t *testing.T) {
+	state := NewGameState()
+	state.Turn = MaxTurns - 15
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.UpdateMyDrone(0, 5000, 1500, 0, 30)
+	drone, fish := state.GetDrone(0), state.GetCreature(4)
+
+	for y := 2500; y <= MapSize; y += 100 {
+		fish.Pos = V(5300, y)
+		turn, ok := state.earliestSave(state.MyDrones, fish)
+		want := ok && turn+EndgameMargin < MaxTurns
+		if got := drone.CanDeliverInTime(state, fish); got != want {
+			t.Errorf("Expected delivery of a fish at depth %d in time to be %t as it is saved on turn %d, got %t", y, want, turn, got)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	HistoryLength       = 10  // positions kept per foe drone
+	HeadingTurns        = 3   // moves averaged to get the heading
+	IntentMinCosine     = 0.8 // how well a fish has to line up with the heading to be the likely target
+	FoeContestedPenalty = 0.5 // share of the value kept for fish the foe will scan before me
+)
+
+// FoeIntent is what a foe drone is believed to be doing from its recent moves.
+type FoeIntent struct {
+	DroneId       int
+	Surfacing     bool
+	Target        *Creature
+	TurnsToTarget int
+}
+
+// RecordPosition appends the current position to the trajectory of the drone.
+func (drone *Drone) RecordPosition() {
+	drone.History = append(drone.History, drone.Pos)
+	if len(drone.History) > HistoryLength {
+		drone.History = drone.History[len(drone.History)-HistoryLength:]
+	}
+}
+
+// Heading returns the average move of the drone over the last turns, zero if it has no history.
+func (drone *Drone) Heading() Vec2 {
+	if len(drone.History) < 2 {
+		return Vec2{}
+	}
+	turns := min(HeadingTurns, len(drone.History)-1)
+	last, first := drone.History[len(drone.History)-1], drone.History[len(drone.History)-1-turns]
+	return last.Sub(first).Scale(1 / float64(turns))
+}
+
+// InferFoeIntent guesses from the heading and scans of a foe drone whether it surfaces or which fish it goes for.
+func (state *GameState) InferFoeIntent(drone *Drone) FoeIntent {
+	intent := FoeIntent{DroneId: drone.Id}
+	heading := drone.Heading()
+	if drone.IsEmergency() || heading.IsZero() {
+		return intent
+	}
+
+	// Mostly going up with scans on board means delivering
+	if len(drone.Scans) > 0 && heading.Y < 0 && -heading.Y >= 2*math.Abs(heading.X) {
+		intent.Surfacing = true
+		return intent
+	}
+
+	bestCosine := IntentMinCosine
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster || creature.Dead || creature.IsDeliveredByFoe(state) || creature.IsScannedByFoe(state) {
+			continue
+		}
+		to := creature.Pos.Sub(drone.Pos)
+		if to.IsZero() {
+			continue
+		}
+		if cosine := heading.Normalize().Dot(to.Normalize()); cosine > bestCosine {
+			bestCosine = cosine
+			intent.Target = creature
+		}
+	}
+	if intent.Target != nil {
+		reach := max(0, int(drone.Pos.Dist(intent.Target.Pos))-ScanRadius)
+		intent.TurnsToTarget = (reach + DroneMovement - 1) / DroneMovement
+	}
+	return intent
+}
+
+// FoeWillScan returns true if a foe drone is likely to scan the creature within the given number of turns.
+func (state *GameState) FoeWillScan(creature *Creature, turns int) bool {
+	for _, drone := range state.FoeDrones {
+		if drone.Pos.Dist(creature.Pos) <= ScanRadius {
+			return true
+		}
+		intent := state.InferFoeIntent(drone)
+		if intent.Target != nil && intent.Target.Id == creature.Id && intent.TurnsToTarget <= turns {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFoeSurfacing returns true if any foe drone carrying a scan of the creature is heading for the surface.
+func (state *GameState) IsFoeSurfacing(creature *Creature) bool {
+	for _, drone := range state.FoeDrones {
+		if containsCreature(drone.Scans, creature.Id) && state.InferFoeIntent(drone).Surfacing {
+			return true
+		}
+	}
+	return false
+}
+
+// FoeTurnRecord is what was deduced about a foe drone on one turn from its battery and scans.
+type FoeTurnRecord struct {
+	Turn          int
+	Battery       int
+	LightUsed     bool
+	ProbableScans []*Creature
+	AggroRisk     float64
+}
+
+// TrackFoeBehavior records for each foe drone whether it lit its light this turn, judging by the battery
+// spent, the fish it probably just scanned and how likely monsters around it were drawn to it.
+func (state *GameState) TrackFoeBehavior() {
+	for _, drone := range state.FoeDrones {
+		record := FoeTurnRecord{Turn: state.Turn, Battery: drone.Battery}
+		// Lighting costs battery, otherwise it recharges
+		record.LightUsed = !drone.IsEmergency() && drone.PrevBattery-drone.Battery == LightBatteryCost
+		radius := ScanRadius
+		if record.LightUsed {
+			radius = LightScanRadius
+		}
+
+		for _, scan := range drone.Scans {
+			if !containsCreature(drone.PrevScans, scan.Id) {
+				record.ProbableScans = append(record.ProbableScans, scan)
+			}
+		}
+		if len(record.ProbableScans) == 0 && record.LightUsed {
+			// Foe scans come with the same turn's input, so the light caught nothing new: keep the fish
+			// estimated within it that the foe still lacks, the ones the light was most likely aimed at
+			for _, creature := range state.Creatures {
+				if creature.Type != Monster && !creature.Dead && !creature.IsScannedByFoe(state) && !creature.IsDeliveredByFoe(state) &&
+					creature.ScanProbability(drone.Pos, radius) >= ScanPredictionThreshold {
+					record.ProbableScans = append(record.ProbableScans, creature)
+				}
+			}
+		}
+
+		for _, monster := range state.GetMonsters() {
+			if dist := drone.Pos.Dist(monster.Pos); dist <= float64(radius) {
+				record.AggroRisk += 1 - float64(dist)/float64(radius+1)
+			}
+		}
+
+		drone.PrevScans = append([]*Creature{}, drone.Scans...)
+		drone.Behavior = append(drone.Behavior, record)
+		if len(drone.Behavior) > HistoryLength {
+			drone.Behavior = drone.Behavior[len(drone.Behavior)-HistoryLength:]
+		}
+	}
+}
+
+// LastBehavior returns the latest record of a foe drone, false if nothing was recorded yet.
+func (drone *Drone) LastBehavior() (FoeTurnRecord, bool) {
+	if len(drone.Behavior) == 0 {
+		return FoeTurnRecord{}, false
+	}
+	return drone.Behavior[len(drone.Behavior)-1], true
+}
+
+// String returns a string representation of the record with field names.
+func (record FoeTurnRecord) String() string {
+	return fmt.Sprintf("FoeTurnRecord{Turn: %d, Battery: %d, LightUsed: %t, ProbableScans: %v, AggroRisk: %.2f}",
+		record.Turn, record.Battery, record.LightUsed, creatureIds(record.ProbableScans), record.AggroRisk)
+}
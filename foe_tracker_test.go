@@ -0,0 +1,94 @@
+package main
+
+import "testing"
+
+func TestInferFoeIntent_TargetAhead(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, DeepFish))
+	state.UpdateCreature(4, 2000, 8000, 0, 0)
+	state.UpdateCreature(5, 8000, 8000, 0, 0)
+	for _, y := range []int{1000, 1600, 2200} {
+		state.UpdateFoeDrone(1, 2000, y, 0, 30)
+	}
+
+	intent := state.InferFoeIntent(state.GetDrone(1))
+
+	if intent.Surfacing || intent.Target == nil || intent.Target.Id != 4 {
+		t.Fatalf("Expected foe to go for fish 4, got %+v", intent)
+	}
+	if intent.TurnsToTarget != 9 {
+		t.Errorf("Expected 9 turns to scan fish 4, got %d", intent.TurnsToTarget)
+	}
+	if !state.FoeWillScan(state.GetCreature(4), 10) || state.FoeWillScan(state.GetCreature(4), 5) || state.FoeWillScan(state.GetCreature(5), 10) {
+		t.Errorf("Expected foe to scan only fish 4 within 10 turns")
+	}
+}
+
+func TestInferFoeIntent_Surfacing(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	for _, y := range []int{8000, 7400, 6800} {
+		state.UpdateFoeDrone(1, 5000, y, 0, 30)
+	}
+	state.GetDrone(1).AddScan(state.GetCreature(4))
+
+	if intent := state.InferFoeIntent(state.GetDrone(1)); !intent.Surfacing {
+		t.Errorf("Expected foe to be surfacing, got %+v", intent)
+	}
+}
+
+func TestTrackFoeBehavior_LightFromBatteryDrop(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(16, 5000, 5000, 0, 0)
+	state.UpdateFoeDrone(1, 5000, 3500, 0, 30)
+	state.TrackFoeBehavior()
+
+	state.UpdateFoeDrone(1, 5000, 4000, 0, 25)
+	state.GetDrone(1).AddScan(state.GetCreature(4))
+	state.TrackFoeBehavior()
+
+	record, _ := state.GetDrone(1).LastBehavior()
+	if !record.LightUsed {
+		t.Errorf("Expected light to be used when battery dropped by %d", LightBatteryCost)
+	}
+	if len(record.ProbableScans) != 1 || record.ProbableScans[0].Id != 4 {
+		t.Errorf("Expected fish 4 to be just scanned, got %v", creatureIds(record.ProbableScans))
+	}
+	if record.AggroRisk <= 0 {
+		t.Errorf("Expected monster within light radius to be a risk")
+	}
+
+	state.UpdateFoeDrone(1, 5000, 4300, 0, 26)
+	state.TrackFoeBehavior()
+
+	record, _ = state.GetDrone(1).LastBehavior()
+	if record.LightUsed || len(record.ProbableScans) != 0 {
+		t.Errorf("Expected no light and no new scans when recharging, got %v", record)
+	}
+}
+
+func TestTrackFoeBehavior_LightWithoutNewScan(t *testing.T) {
+	state := NewGameState()
+	for id := 4; id <= 6; id++ {
+		state.AddCreature(NewCreature(id, id-4, MediumFish))
+		state.UpdateCreature(id, 4000+500*(id-4), 5000, 0, 0)
+	}
+	state.UpdateFoeDrone(1, 5000, 4000, 0, 30)
+	state.GetDrone(1).AddScan(state.GetCreature(4))
+	state.AddFoeScan(5)
+	state.TrackFoeBehavior()
+
+	state.UpdateFoeDrone(1, 5000, 4300, 0, 25)
+	state.TrackFoeBehavior()
+
+	record, _ := state.GetDrone(1).LastBehavior()
+	if !record.LightUsed {
+		t.Fatalf("Expected light to be used when battery dropped by %d", LightBatteryCost)
+	}
+	if len(record.ProbableScans) != 1 || record.ProbableScans[0].Id != 6 {
+		t.Errorf("Expected only fish 6 the foe lacks within its light, got %v", creatureIds(record.ProbableScans))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import "math/bits"
+
+// IdSet is a set of creature ids, creature ids stay below 64 in every league.
+type IdSet uint64
+
+// Add returns the set with the id added, ids out of range are ignored.
+func (set IdSet) Add(id int) IdSet {
+	if id < 0 || id >= 64 {
+		return set
+	}
+	return set | 1<<uint(id)
+}
+
+// Has returns true if the id is in the set.
+func (set IdSet) Has(id int) bool {
+	return id >= 0 && id < 64 && set&(1<<uint(id)) != 0
+}
+
+// Len returns the number of ids in the set.
+func (set IdSet) Len() int {
+	return bits.OnesCount64(uint64(set))
+}
+
+// idSetOf returns the set of the ids of the creatures.
+func idSetOf(creatures []*Creature) IdSet {
+	var set IdSet
+	for _, creature := range creatures {
+		set = set.Add(creature.Id)
+	}
+	return set
+}
+
+// stateIndex keeps the creatures and drones in arrays indexed by id and the scans of both players as
+// id sets, it is kept up to date by the GameState methods adding and clearing them.
+type stateIndex struct {
+	creatures []*Creature
+	drones    []*Drone
+	mySaved   IdSet
+	foeSaved  IdSet
+	sets      []CreatureSet
+}
+
+// creature returns the creature with the id, nil if unknown.
+func (index *stateIndex) creature(id int) *Creature {
+	if id < 0 || id >= len(index.creatures) {
+		return nil
+	}
+	return index.creatures[id]
+}
+
+// drone returns the drone with the id, nil if unknown.
+func (index *stateIndex) drone(id int) *Drone {
+	if id < 0 || id >= len(index.drones) {
+		return nil
+	}
+	return index.drones[id]
+}
+
+// addCreature indexes the creature by its id.
+func (index *stateIndex) addCreature(creature *Creature) {
+	for len(index.creatures) <= creature.Id {
+		index.creatures = append(index.creatures, nil)
+	}
+	index.creatures[creature.Id] = creature
+}
+
+// addDrone indexes the drone by its id.
+func (index *stateIndex) addDrone(drone *Drone) {
+	for len(index.drones) <= drone.Id {
+		index.drones = append(index.drones, nil)
+	}
+	index.drones[drone.Id] = drone
+}
+
+// Carried returns the scans held by the drones and not saved yet.
+func Carried(drones []*Drone) IdSet {
+	var set IdSet
+	for _, drone := range drones {
+		set |= drone.scanned
+	}
+	return set
+}
+
+// Targeted returns the creatures my drones go for, except the target of skipDrone if not nil.
+func (state *GameState) Targeted(skipDrone *Drone) IdSet {
+	var set IdSet
+	for _, drone := range state.MyDrones {
+		if drone.Target != nil && (skipDrone == nil || drone.Id != skipDrone.Id) {
+			set = set.Add(drone.Target.Id)
+		}
+	}
+	return set
+}
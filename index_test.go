@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+// lookupState returns a state with twelve fish, two monsters and two drones each carrying scans.
+func lookupState() *GameState {
+	state := NewGameState()
+	for id := FirstCreatureId; id < FirstCreatureId+FishPairs*2; id++ {
+		state.AddCreature(NewCreature(id, id%4, CreatureType((id-FirstCreatureId)/4)))
+	}
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.AddCreature(NewCreature(17, -1, Monster))
+	for id := 0; id < 4; id++ {
+		if id%2 == 0 {
+			state.UpdateMyDrone(id, 2000+id*1000, 3000, 0, 30)
+		} else {
+			state.UpdateFoeDrone(id, 2000+id*1000, 3000, 0, 30)
+		}
+	}
+	state.AddMyScan(4)
+	state.AddFoeScan(5)
+	state.GetDrone(0).AddScan(state.GetCreature(6))
+	state.GetDrone(1).AddScan(state.GetCreature(7))
+	state.GetDrone(2).Target = state.GetCreature(8)
+	return state
+}
+
+func BenchmarkStateLookups(b *testing.B) {
+	state := lookupState()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, creature := range state.Creatures {
+			state.GetCreature(creature.Id)
+			creature.IsScanned(state)
+			creature.IsDelivered(state)
+			creature.IsDeliveredByFoe(state)
+			creature.IsScannedByFoe(state)
+			creature.IsTargeted(state, state.MyDrones[0])
+		}
+		for id := 0; id < 4; id++ {
+			state.GetDrone(id)
+		}
+	}
+}
+
+func BenchmarkMarginalValue(b *testing.B) {
+	state := lookupState()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, creature := range state.Creatures {
+			state.MarginalValue(creature)
+		}
+	}
+}
+
+func TestIdSet_AddHasLen(t *testing.T) {
+	var set IdSet
+	set = set.Add(4).Add(15).Add(4).Add(64)
+	if !set.Has(4) || !set.Has(15) || set.Has(5) || set.Has(64) || set.Len() != 2 {
+		t.Errorf("Expected {4, 15}, got %b", set)
+	}
+}
+
+func TestIndex_FollowsScans(t *testing.T) {
+	state := lookupState()
+
+	if !state.GetCreature(4).IsDelivered(state) || !state.GetCreature(5).IsDeliveredByFoe(state) {
+		t.Errorf("Expected 4 saved by me and 5 by the foe")
+	}
+	if !state.GetCreature(6).IsScanned(state) || !state.GetCreature(7).IsScannedByFoe(state) || state.GetCreature(7).IsScanned(state) {
+		t.Errorf("Expected 6 carried by me and 7 by the foe")
+	}
+	if !state.GetCreature(8).IsTargeted(state, nil) || state.GetCreature(8).IsTargeted(state, state.GetDrone(2)) {
+		t.Errorf("Expected 8 targeted by drone 2 only")
+	}
+
+	state.PrepareForNextTurn()
+	state.GetDrone(0).UpdateEmergency(1)
+	if state.GetCreature(4).IsDelivered(state) || state.GetCreature(6).IsScanned(state) || state.GetCreature(7).IsScannedByFoe(state) {
+		t.Errorf("Expected scans to be cleared for the next turn")
+	}
+	if state.GetDrone(9) != nil || state.GetCreature(99) != nil {
+		t.Errorf("Expected unknown ids to be nil")
+	}
+}
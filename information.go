@@ -0,0 +1,62 @@
+package main
+
+const (
+	InformationGainWeight = 0.5         // points a fully resolved estimate of a fish is worth per point of its value
+	PoorlyLocalizedArea   = 3000 * 3000 // estimate area from which the target is worth triangulating
+)
+
+var triangulationAngles = []float64{-90, -60, 60, 90}
+
+// Area returns the surface of the box.
+func (box Box) Area() float64 {
+	return float64(max(0, box.MaxX-box.MinX)) * float64(max(0, box.MaxY-box.MinY))
+}
+
+// expectedAreaAfterBlip returns the expected area of the box once a radar blip from pos tells in which
+// of the parts cut by the vertical and horizontal lines through pos the creature is.
+func expectedAreaAfterBlip(box Box, pos Vec2) float64 {
+	area := box.Area()
+	if area == 0 {
+		return 0
+	}
+	x, y := pos.Ints()
+	splitX := clamp(x, box.MinX, box.MaxX)
+	splitY := clamp(y, box.MinY, box.MaxY)
+	expected := 0.0
+	for _, part := range []Box{
+		{box.MinX, box.MinY, splitX, splitY},
+		{splitX, box.MinY, box.MaxX, splitY},
+		{box.MinX, splitY, splitX, box.MaxY},
+		{splitX, splitY, box.MaxX, box.MaxY},
+	} {
+		// Chance to be in the part times the area left if it is there
+		expected += part.Area() * part.Area() / area
+	}
+	return expected
+}
+
+// InformationGain returns how much a drone radar at pos is expected to shrink the estimates of the fish
+// left to scan, each weighted by its marginal value.
+func (state *GameState) InformationGain(pos Vec2) float64 {
+	gain := 0.0
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster || creature.Dead {
+			continue
+		}
+		area := creature.Bounds.Area()
+		if area == 0 {
+			continue
+		}
+		value := state.MarginalValue(creature)
+		if value == 0 {
+			continue
+		}
+		gain += value * (area - expectedAreaAfterBlip(creature.Bounds, pos)) / area
+	}
+	return gain
+}
+
+// IsPoorlyLocalized returns true if the estimate of the creature is too wide to head straight for it.
+func (creature *Creature) IsPoorlyLocalized() bool {
+	return creature.Bounds.Area() >= PoorlyLocalizedArea
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestExpectedAreaAfterBlip(t *testing.T) {
+	box := Box{0, 2500, 4000, 6500}
+
+	if area := expectedAreaAfterBlip(box, V(2000, 4500)); area != box.Area()/4 {
+		t.Errorf("Expected a quarter of the area from the center, got %.0f", area)
+	}
+	if area := expectedAreaAfterBlip(box, V(5000, 1000)); area != box.Area() {
+		t.Errorf("Expected no gain from outside the box, got %.0f", area)
+	}
+}
+
+func TestInformationGain_HigherInsideEstimate(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.GetCreature(4).Bounds = Box{0, 7500, 4000, 10000}
+
+	inside := state.InformationGain(V(2000, 8750))
+	outside := state.InformationGain(V(6000, 5000))
+
+	if inside <= outside || outside != 0 {
+		t.Errorf("Expected gain only from inside the estimate, got %.2f inside and %.2f outside", inside, outside)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	LightMinGain        = 0.5 // expected points lighting has to bring after costs
+	LightMonsterRisk    = 4.0 // points a monster noticing the drone costs on top of the carried scans
+	LightHorizon        = 8   // turns of the dive ahead the battery is budgeted over
+	LightFutureDiscount = 0.8 // weight of points expected one turn later, the course may change before
+)
+
+// LightPlan is the light decision of a drone for the current turn and what it was based on.
+type LightPlan struct {
+	Light         bool    `json:"light"`
+	ExpectedScans float64 `json:"expectedScans"`
+	ExpectedGain  float64 `json:"expectedGain"`
+	Risk          float64 `json:"risk"`
+	Forgone       float64 `json:"forgone"` // points the rest of the dive is expected to lose by lighting now
+	Reason        string  `json:"reason"`
+}
+
+// lightSlot is a later turn of the dive the drone may light on, with the chance lighting then scans each
+// fish the dark radius does not.
+type lightSlot struct {
+	Turn  int
+	Extra []float64
+}
+
+// PlanLight decides if lighting at pos at the end of the turn is worth the battery and the risk of
+// attracting monsters. Lighting is expected to bring the unscanned fish only within the lit radius, and
+// to cost the points the battery would have brought further down the dive: the drone is assumed to keep
+// sinking to the deepest habitat with fish left, lighting on the turns that bring the most as long as
+// the battery and its recharge allow.
+func (drone *Drone) PlanLight(state *GameState, pos Vec2) LightPlan {
+	plan := LightPlan{}
+	minGain := LightMinGain
+	if state.IsEndgame() {
+		// Battery left at the end of the game is worth nothing
+		minGain = 0
+	}
+	if drone.Battery < LightBatteryCost {
+		plan.Reason = "battery low"
+		return plan
+	}
+
+	var fish []*Creature
+	var points []float64
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster || creature.Dead || creature.IsScanned(state) || creature.IsDelivered(state) {
+			continue
+		}
+		extra := lightExtra(creature, pos)
+		value := float64(getScanPoints(creature.Type, !creature.IsDeliveredByFoe(state)))
+		plan.ExpectedScans += extra
+		plan.ExpectedGain += extra * value
+		fish, points = append(fish, creature), append(points, value)
+	}
+	if plan.ExpectedScans == 0 {
+		plan.Reason = "no fish in reach"
+		return plan
+	}
+
+	// Monsters within the dark radius notice the drone anyway but the light keeps it in their sight,
+	// further ones less the closer they are to the edge of the lit radius
+	for _, monster := range state.GetMonsters() {
+		if monster.Dead {
+			continue
+		}
+		dist := pos.Dist(monster.Pos)
+		if dist <= ScanRadius {
+			plan.Risk++
+		} else if dist <= LightScanRadius {
+			plan.Risk += 1 - (dist-ScanRadius)/(LightScanRadius-ScanRadius)
+		}
+	}
+	riskCost := plan.Risk * (LightMonsterRisk + float64(drone.CarriedPoints(state)))
+
+	// The rest of the dive with the battery kept against what is left of it after lighting now
+	slots := diveSlots(state, pos, fish)
+	missed := make([]float64, len(fish))
+	for i := range missed {
+		missed[i] = 1
+	}
+	kept := bestLightUse(slots, points, missed, min(DroneMaxBattery, drone.Battery+DroneBatteryRegen), 0)
+	spent := bestLightUse(slots, points, missed, drone.Battery-LightBatteryCost, 0)
+	plan.Forgone = math.Max(0, kept-spent)
+
+	gain := plan.ExpectedGain - riskCost - plan.Forgone
+	plan.Light = gain >= minGain
+	switch {
+	case plan.Light:
+		plan.Reason = fmt.Sprintf("expecting %.1f scans", plan.ExpectedScans)
+	case riskCost > plan.Forgone:
+		plan.Reason = "monster risk"
+	case plan.Forgone > 0:
+		plan.Reason = "saving battery"
+	default:
+		plan.Reason = "too few fish in reach"
+	}
+	return plan
+}
+
+// lightExtra returns the chance that lighting at pos scans the creature and the dark radius does not.
+func lightExtra(creature *Creature, pos Vec2) float64 {
+	return creature.ScanProbability(pos, LightScanRadius) - creature.ScanProbability(pos, ScanRadius)
+}
+
+// diveSlots returns the turns after this one a drone may light on as it keeps sinking from pos down to
+// the middle of the deepest habitat of the fish, within LightHorizon turns and the end of the game.
+func diveSlots(state *GameState, pos Vec2, fish []*Creature) []lightSlot {
+	bottom := 0
+	for _, creature := range fish {
+		minY, maxY := creatureDepths(creature.Type)
+		bottom = max(bottom, (minY+maxY)/2)
+	}
+	var slots []lightSlot
+	for turn := 1; turn <= LightHorizon && state.Turn+turn < MaxTurns; turn++ {
+		depth := pos.Y + float64(turn*DroneMovement)
+		if depth-DroneMovement >= float64(bottom) {
+			break
+		}
+		at := Vec2{pos.X, math.Min(depth, float64(bottom))}
+		slot := lightSlot{Turn: turn, Extra: make([]float64, len(fish))}
+		for i, creature := range fish {
+			slot.Extra[i] = lightExtra(creature, at)
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// bestLightUse returns the most points lighting on the slots can be expected to bring, starting with the
+// battery left after the given turn, each fish being missed so far with the given chance. Points expected
+// later are discounted by LightFutureDiscount each turn.
+func bestLightUse(slots []lightSlot, points, missed []float64, battery, turn int) float64 {
+	if len(slots) == 0 {
+		return 0
+	}
+	slot := slots[0]
+	// The battery recharges on the turns between the slots, the light staying off
+	battery = min(DroneMaxBattery, battery+(slot.Turn-turn-1)*DroneBatteryRegen)
+	best := bestLightUse(slots[1:], points, missed, min(DroneMaxBattery, battery+DroneBatteryRegen), slot.Turn)
+	if battery < LightBatteryCost {
+		return best
+	}
+
+	gain := 0.0
+	left := make([]float64, len(missed))
+	for i := range missed {
+		gain += points[i] * missed[i] * slot.Extra[i]
+		left[i] = missed[i] * (1 - slot.Extra[i])
+	}
+	if gain == 0 {
+		return best
+	}
+	gain *= math.Pow(LightFutureDiscount, float64(slot.Turn))
+	return math.Max(best, gain+bestLightUse(slots[1:], points, left, battery-LightBatteryCost, slot.Turn))
+}
+
+// BatteryReserve returns the battery to keep for lighting deeper habitats that still have fish to scan.
+func (drone *Drone) BatteryReserve(state *GameState) int {
+	reserve := 0
+	for _, fishType := range []CreatureType{ShallowFish, MediumFish, DeepFish} {
+		if float64(fishDepthsByType[fishType][0]) <= drone.Pos.Y {
+			continue
+		}
+		for _, creature := range state.Creatures {
+			if creature.Type == fishType && !creature.Dead && !creature.IsScanned(state) && !creature.IsDelivered(state) {
+				reserve += LightBatteryCost
+				break
+			}
+		}
+	}
+	return reserve
+}
+
+// CarriedPoints returns the base points of the scans the drone holds and has not delivered yet.
+func (drone *Drone) CarriedPoints(state *GameState) int {
+	points := 0
+	for _, scan := range drone.Scans {
+		if !scan.IsDelivered(state) {
+			points += getScanPoints(scan.Type, false)
+		}
+	}
+	return points
+}
+
+// String returns a short representation of the plan for logging.
+func (plan LightPlan) String() string {
+	return fmt.Sprintf("LightPlan{Light: %t, Scans: %.2f, Gain: %.2f, Risk: %.2f, Forgone: %.2f, Reason: %s}",
+		plan.Light, plan.ExpectedScans, plan.ExpectedGain, plan.Risk, plan.Forgone, plan.Reason)
+}
@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestPlanLight_FishOnlyWithinLitRadius(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateCreature(4, 6500, 4000, 0, 0)
+
+	plan := state.GetDrone(0).PlanLight(state, V(5000, 4000))
+
+	if !plan.Light {
+		t.Errorf("Expected light to be used, got %v", plan)
+	}
+}
+
+func TestPlanLight_NoFishInReach(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateCreature(4, 500, 3000, 0, 0)
+
+	plan := state.GetDrone(0).PlanLight(state, V(5000, 4000))
+
+	if plan.Light {
+		t.Errorf("Expected light to stay off, got %v", plan)
+	}
+}
+
+func TestPlanLight_MonsterWithinDarkRadius(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, -1, Monster))
+	state.UpdateCreature(4, 6500, 4000, 0, 0)
+	state.UpdateCreature(5, 4500, 4000, 0, 0)
+
+	plan := state.GetDrone(0).PlanLight(state, V(5000, 4000))
+
+	if plan.Light || plan.Risk != 1 {
+		t.Errorf("Expected the monster within the dark radius to count as a full risk, got %v", plan)
+	}
+}
+
+func TestPlanLight_MonsterRiskOutweighsScan(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, -1, Monster))
+	state.UpdateCreature(4, 6500, 4000, 0, 0)
+	state.UpdateCreature(5, 3900, 4000, 0, 0)
+
+	plan := state.GetDrone(0).PlanLight(state, V(5000, 4000))
+
+	if plan.Light {
+		t.Errorf("Expected light to stay off because of the monster, got %v", plan)
+	}
+}
+
+func TestPlanLight_SavesBatteryForDeeperFish(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 3000, 0, LightBatteryCost)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(6, 0, MediumFish))
+	state.AddCreature(NewCreature(7, 1, MediumFish))
+	state.UpdateCreature(4, 6500, 3000, 0, 0)
+	// Only within the lit radius four turns further down the dive
+	state.UpdateCreature(6, 6900, 5400, 0, 0)
+	state.UpdateCreature(7, 3100, 5400, 0, 0)
+	drone := state.GetDrone(0)
+
+	plan := drone.PlanLight(state, V(5000, 3000))
+
+	if plan.Light || plan.Forgone == 0 || plan.Reason != "saving battery" {
+		t.Errorf("Expected the battery to be saved for the medium fish, got %v", plan)
+	}
+
+	// With battery for both there is nothing to save it for
+	drone.Battery = DroneMaxBattery
+	plan = drone.PlanLight(state, V(5000, 3000))
+
+	if !plan.Light || plan.Forgone != 0 {
+		t.Errorf("Expected light to be used with a full battery, got %v", plan)
+	}
+}
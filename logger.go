@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LogLevel is how important a log entry is, entries below the level of the logger are dropped.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+)
+
+var levelNames = map[LogLevel]string{LevelDebug: "debug", LevelInfo: "info", LevelWarn: "warn"}
+
+// LogCategory is the part of the bot a log entry comes from.
+type LogCategory string
+
+const (
+	CategoryParse    LogCategory = "parse"    // input read from the referee and the state built from it
+	CategoryEstimate LogCategory = "estimate" // creature position estimates
+	CategoryTarget   LogCategory = "target"   // choice of fish to scan and of the move towards it
+	CategoryAvoid    LogCategory = "avoid"    // monster danger and detours
+	CategoryScore    LogCategory = "score"    // projected scores and surfacing decisions
+	CategoryLight    LogCategory = "light"    // light decisions
+	CategoryDecision LogCategory = "decision" // trace of each drone command and why it was chosen
+	CategoryTool     LogCategory = "tool"     // local commands like the arena
+)
+
+var logCategories = []LogCategory{CategoryParse, CategoryEstimate, CategoryTarget, CategoryAvoid, CategoryScore, CategoryLight, CategoryDecision, CategoryTool}
+
+const (
+	// LogEnv is the environment variable the logger configuration is read from, e.g. "debug,target,avoid,json".
+	LogEnv = "BOT_LOG"
+	// LogDefault is the configuration without LogEnv, as on CodinGame where the environment cannot be set.
+	LogDefault = "info"
+)
+
+// logWriter is where the logger writes, tools running bots in-process may silence it.
+var logWriter io.Writer = os.Stderr
+
+// logger is the logger of the bot, configured once at start.
+var logger = NewLogger()
+
+// Logger writes log entries at or above its level for the enabled categories, as text lines or as
+// JSON lines that can be analyzed from replays. Each category may have its own level.
+type Logger struct {
+	Level      LogLevel
+	Categories map[LogCategory]LogLevel // levels of the enabled categories, nil enables all at Level
+	JSON       bool
+	Summarize  bool // the default strategy replaces the message of each command with a decision summary
+	Turn       int
+}
+
+// LogEntry is one log entry as written in JSON mode.
+type LogEntry struct {
+	Turn     int            `json:"turn"`
+	Level    string         `json:"level"`
+	Category LogCategory    `json:"category"`
+	Message  string         `json:"message"`
+	Fields   map[string]any `json:"fields,omitempty"`
+}
+
+// NewLogger returns a text logger of info entries for all categories.
+func NewLogger() *Logger {
+	return &Logger{Level: LevelInfo}
+}
+
+// ParseLogger reads a logger configuration: a comma separated list of a level (debug, info, warn),
+// categories to enable, optionally with their own level as category=level, json for JSON lines and
+// summarize to show the summary of each decision next to the drone in the CodinGame viewer.
+func ParseLogger(spec string) (*Logger, error) {
+	result := NewLogger()
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if item == "json" {
+			result.JSON = true
+			continue
+		}
+		if item == "summarize" {
+			result.Summarize = true
+			continue
+		}
+		if level, ok := parseLevel(item); ok {
+			result.Level = level
+			continue
+		}
+		name, levelName, hasLevel := strings.Cut(item, "=")
+		category := LogCategory(name)
+		if !isLogCategory(category) {
+			return nil, fmt.Errorf("unknown log category or level %q", item)
+		}
+		level := LogLevel(-1)
+		if hasLevel {
+			var ok bool
+			if level, ok = parseLevel(levelName); !ok {
+				return nil, fmt.Errorf("unknown log level %q", levelName)
+			}
+		}
+		if result.Categories == nil {
+			result.Categories = map[LogCategory]LogLevel{}
+		}
+		result.Categories[category] = level
+	}
+	// Categories without their own level follow the global one
+	for category, level := range result.Categories {
+		if level < 0 {
+			result.Categories[category] = result.Level
+		}
+	}
+	return result, nil
+}
+
+// ConfigureLogger sets up the logger from the environment or LogDefault, keeping the default on errors.
+func ConfigureLogger() {
+	spec, ok := os.LookupEnv(LogEnv)
+	if !ok {
+		spec = LogDefault
+	}
+	configured, err := ParseLogger(spec)
+	if err != nil {
+		logger.Warn(CategoryTool, LogEnv+":", err)
+		return
+	}
+	logger = configured
+}
+
+func parseLevel(name string) (LogLevel, bool) {
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+func isLogCategory(category LogCategory) bool {
+	for _, known := range logCategories {
+		if known == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns true if entries of the level and category are written.
+func (l *Logger) Enabled(level LogLevel, category LogCategory) bool {
+	if l.Categories == nil {
+		return level >= l.Level
+	}
+	minLevel, ok := l.Categories[category]
+	return ok && level >= minLevel
+}
+
+// Debug logs the messages separated by spaces at debug level.
+func (l *Logger) Debug(category LogCategory, messages ...any) {
+	l.write(LevelDebug, category, messages, nil)
+}
+
+// Info logs the messages separated by spaces at info level.
+func (l *Logger) Info(category LogCategory, messages ...any) {
+	l.write(LevelInfo, category, messages, nil)
+}
+
+// Warn logs the messages separated by spaces at warn level.
+func (l *Logger) Warn(category LogCategory, messages ...any) {
+	l.write(LevelWarn, category, messages, nil)
+}
+
+// Fields logs a message with named values, kept apart in JSON mode and appended as key=value otherwise.
+func (l *Logger) Fields(level LogLevel, category LogCategory, message string, fields map[string]any) {
+	l.write(level, category, []any{message}, fields)
+}
+
+func (l *Logger) write(level LogLevel, category LogCategory, messages []any, fields map[string]any) {
+	if !l.Enabled(level, category) {
+		return
+	}
+	message := strings.TrimSuffix(fmt.Sprintln(messages...), "\n")
+	if l.JSON {
+		line, err := json.Marshal(LogEntry{Turn: l.Turn, Level: levelNames[level], Category: category, Message: message, Fields: fields})
+		if err != nil {
+			line, _ = json.Marshal(LogEntry{Turn: l.Turn, Level: levelNames[level], Category: category, Message: message + " " + err.Error()})
+		}
+		_, _ = fmt.Fprintln(logWriter, string(line))
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		message += fmt.Sprintf(" %s=%v", key, fields[key])
+	}
+	_, _ = fmt.Fprintf(logWriter, "[%s %s] %s\n", levelNames[level], category, message)
+}
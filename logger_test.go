@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// captureLog redirects the logger output for the duration of the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buffer bytes.Buffer
+	previous := logWriter
+	logWriter = &buffer
+	t.Cleanup(func() { logWriter = previous })
+	return &buffer
+}
+
+func TestParseLogger(t *testing.T) {
+	l, err := ParseLogger("debug, target, avoid=warn, json, summarize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Level != LevelDebug || !l.JSON || !l.Summarize {
+		t.Errorf("Expected debug JSON logger summarizing decisions, got %+v", l)
+	}
+	if !l.Enabled(LevelDebug, CategoryTarget) || l.Enabled(LevelInfo, CategoryAvoid) || !l.Enabled(LevelWarn, CategoryAvoid) {
+		t.Errorf("Expected target at debug and avoid at warn, got %v", l.Categories)
+	}
+	if l.Enabled(LevelWarn, CategoryScore) {
+		t.Error("Expected categories not listed to be disabled")
+	}
+
+	l, err = ParseLogger("")
+	if err != nil || l.Enabled(LevelDebug, CategoryParse) || !l.Enabled(LevelInfo, CategoryParse) || l.Summarize {
+		t.Errorf("Expected default to log info of all categories, got %+v %v", l, err)
+	}
+
+	for _, spec := range []string{"verbose", "target=loud"} {
+		if _, err := ParseLogger(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestLogger_Text(t *testing.T) {
+	buffer := captureLog(t)
+	l := &Logger{Level: LevelInfo}
+	l.Debug(CategoryTarget, "hidden")
+	l.Info(CategoryTarget, "Drone", 1, "targets", 4)
+	l.Fields(LevelWarn, CategoryEstimate, "contradiction", map[string]any{"y": 2, "x": 1})
+
+	expected := "[info target] Drone 1 targets 4\n[warn estimate] contradiction x=1 y=2\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	buffer := captureLog(t)
+	l := &Logger{Level: LevelDebug, JSON: true, Turn: 7}
+	l.Debug(CategoryAvoid, "danger", 0.5)
+	l.Fields(LevelInfo, CategoryScore, "surfacing", map[string]any{"turns": 3})
+
+	lines := strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %q", buffer.String())
+	}
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Turn != 7 || entry.Level != "debug" || entry.Category != CategoryAvoid || entry.Message != "danger 0.5" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Fields["turns"] != 3.0 {
+		t.Errorf("Expected turns field, got %+v", entry)
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 )
 
 /**
@@ -9,97 +12,145 @@ import (
  **/
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+	ConfigureLogger()
+	RunBot(os.Stdin, os.Stdout, NewDefaultStrategy())
+}
+
+// runCommand runs one of the local tooling commands and returns the exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "arena":
+		return runArena(args)
+	case "visualize":
+		return runVisualize(args)
+	case "step":
+		return runStep(args)
+	case "stats":
+		return runStats(args)
+	}
+	logger.Warn(CategoryTool, "Unknown command:", name)
+	return 2
+}
+
+// RunBot plays a whole game reading the referee input from in and writing drone commands to out.
+// It returns when the input is exhausted.
+func RunBot(in io.Reader, out io.Writer, strategy Strategy) {
+	reader := bufio.NewReader(in)
 	state := NewGameState()
+	state.Out = out
+	if err := state.ReadInit(reader); err != nil {
+		return
+	}
+	for {
+		state.PrepareForNextTurn()
+		if err := state.ReadTurn(reader); err != nil {
+			return
+		}
+		state.PlayTurn(strategy)
+		state.MoveAll()
+	}
+}
+
+// PlayTurn updates the beliefs of the bot with the input just read and lets the strategy write the commands.
+func (state *GameState) PlayTurn(strategy Strategy) {
+	state.NextTurn()
+	logger.Turn = state.Turn
+	state.EstimateAll()
+	state.TrackFoeBehavior()
+
+	state.Print()
+	strategy.Play(state)
+}
+
+// ReadInit reads the creatures sent before the first turn.
+func (state *GameState) ReadInit(reader io.Reader) error {
 	var creatureCount int
-	fmt.Scan(&creatureCount)
+	if _, err := fmt.Fscan(reader, &creatureCount); err != nil {
+		return err
+	}
 
 	for i := 0; i < creatureCount; i++ {
 		var creatureId, color, _type int
-		fmt.Scan(&creatureId, &color, &_type)
+		fmt.Fscan(reader, &creatureId, &color, &_type)
 		state.AddCreature(NewCreature(creatureId, color, CreatureType(_type)))
 	}
-	for {
-		state.PrepareForNextTurn()
-		var myScore int
-		fmt.Scan(&myScore)
-		state.MyScore = myScore
+	return nil
+}
 
-		var foeScore int
-		fmt.Scan(&foeScore)
-		state.FoeScore = foeScore
+// ReadTurn reads the input of one turn into the state, it fails only when the input is exhausted.
+func (state *GameState) ReadTurn(reader io.Reader) error {
+	var myScore int
+	if _, err := fmt.Fscan(reader, &myScore); err != nil {
+		return err
+	}
+	state.MyScore = myScore
 
-		var myScanCount int
-		fmt.Scan(&myScanCount)
+	var foeScore int
+	fmt.Fscan(reader, &foeScore)
+	state.FoeScore = foeScore
 
-		for i := 0; i < myScanCount; i++ {
-			var creatureId int
-			fmt.Scan(&creatureId)
-			state.AddMyScan(creatureId)
-		}
-		var foeScanCount int
-		fmt.Scan(&foeScanCount)
+	var myScanCount int
+	fmt.Fscan(reader, &myScanCount)
 
-		for i := 0; i < foeScanCount; i++ {
-			var creatureId int
-			fmt.Scan(&creatureId)
-			state.AddFoeScan(creatureId)
-		}
-		var myDroneCount int
-		fmt.Scan(&myDroneCount)
+	for i := 0; i < myScanCount; i++ {
+		var creatureId int
+		fmt.Fscan(reader, &creatureId)
+		state.AddMyScan(creatureId)
+	}
+	var foeScanCount int
+	fmt.Fscan(reader, &foeScanCount)
 
-		for i := 0; i < myDroneCount; i++ {
-			var droneId, droneX, droneY, emergency, battery int
-			fmt.Scan(&droneId, &droneX, &droneY, &emergency, &battery)
-			state.UpdateMyDrone(droneId, droneX, droneY, emergency, battery)
-		}
-		var foeDroneCount int
-		fmt.Scan(&foeDroneCount)
+	for i := 0; i < foeScanCount; i++ {
+		var creatureId int
+		fmt.Fscan(reader, &creatureId)
+		state.AddFoeScan(creatureId)
+	}
+	var myDroneCount int
+	fmt.Fscan(reader, &myDroneCount)
 
-		for i := 0; i < foeDroneCount; i++ {
-			var droneId, droneX, droneY, emergency, battery int
-			fmt.Scan(&droneId, &droneX, &droneY, &emergency, &battery)
-			state.UpdateFoeDrone(droneId, droneX, droneY, emergency, battery)
-		}
-		var droneScanCount int
-		fmt.Scan(&droneScanCount)
-
-		for i := 0; i < droneScanCount; i++ {
-			var droneId, creatureId int
-			fmt.Scan(&droneId, &creatureId)
-			drone := state.GetDrone(droneId)
-			if drone != nil {
-				drone.AddScan(state.GetCreature(creatureId))
-			}
-		}
-		var visibleCreatureCount int
-		fmt.Scan(&visibleCreatureCount)
+	for i := 0; i < myDroneCount; i++ {
+		var droneId, droneX, droneY, emergency, battery int
+		fmt.Fscan(reader, &droneId, &droneX, &droneY, &emergency, &battery)
+		state.UpdateMyDrone(droneId, droneX, droneY, emergency, battery)
+	}
+	var foeDroneCount int
+	fmt.Fscan(reader, &foeDroneCount)
 
-		for i := 0; i < visibleCreatureCount; i++ {
-			var creatureId, creatureX, creatureY, creatureVx, creatureVy int
-			fmt.Scan(&creatureId, &creatureX, &creatureY, &creatureVx, &creatureVy)
-			state.UpdateCreature(creatureId, creatureX, creatureY, creatureVx, creatureVy)
-		}
-		var radarBlipCount int
-		fmt.Scan(&radarBlipCount)
-
-		for i := 0; i < radarBlipCount; i++ {
-			var droneId, creatureId int
-			var radar string
-			fmt.Scan(&droneId, &creatureId, &radar)
-			state.UpdateRadarBlip(droneId, creatureId, radar)
-		}
-		state.NextTurn()
-		state.EstimateAll()
-
-		state.Print()
-		for i := 0; i < myDroneCount; i++ {
-			drone := state.GetDrone(i)
-			if drone != nil {
-				drone.Move(state)
-			}
+	for i := 0; i < foeDroneCount; i++ {
+		var droneId, droneX, droneY, emergency, battery int
+		fmt.Fscan(reader, &droneId, &droneX, &droneY, &emergency, &battery)
+		state.UpdateFoeDrone(droneId, droneX, droneY, emergency, battery)
+	}
+	var droneScanCount int
+	fmt.Fscan(reader, &droneScanCount)
+
+	for i := 0; i < droneScanCount; i++ {
+		var droneId, creatureId int
+		fmt.Fscan(reader, &droneId, &creatureId)
+		drone := state.GetDrone(droneId)
+		if drone != nil {
+			drone.AddScan(state.GetCreature(creatureId))
 		}
+	}
+	var visibleCreatureCount int
+	fmt.Fscan(reader, &visibleCreatureCount)
 
-		state.MoveAll()
-
+	for i := 0; i < visibleCreatureCount; i++ {
+		var creatureId, creatureX, creatureY, creatureVx, creatureVy int
+		fmt.Fscan(reader, &creatureId, &creatureX, &creatureY, &creatureVx, &creatureVy)
+		state.UpdateCreature(creatureId, creatureX, creatureY, creatureVx, creatureVy)
+	}
+	var radarBlipCount int
+	fmt.Fscan(reader, &radarBlipCount)
+
+	for i := 0; i < radarBlipCount; i++ {
+		var droneId, creatureId int
+		var radar string
+		fmt.Fscan(reader, &droneId, &creatureId, &radar)
+		state.UpdateRadarBlip(droneId, creatureId, radar)
 	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package main
+
+import "math"
+
+// MapMax is the last coordinate on the map, the engine keeps drones and creatures within 0..MapMax.
+const MapMax = MapSize - 1
+
+// The engine works on double precision vectors and snaps them to the grid after every move. Speeds are
+// first rounded to 7 decimals, so a 0.49999999 coming out of a normalization still rounds up, and then
+// rounded half up. Positions are clamped to the map and, for creatures, to their habitat.
+
+// engineRound snaps v to the grid the way the engine does, it is the only rounding rule of the bot and
+// the referee.
+func engineRound(v Vec2) Vec2 {
+	return Vec2{engineRoundValue(v.X), engineRoundValue(v.Y)}
+}
+
+// engineRoundValue rounds value to 7 decimals and then half up.
+func engineRoundValue(value float64) float64 {
+	return math.Floor(math.Round(value*1e7)/1e7 + 0.5)
+}
+
+// DroneMoveSpeed returns the move of a drone at pos heading for target, at most DroneMovement long.
+func DroneMoveSpeed(pos, target Vec2) Vec2 {
+	return engineRound(target.Sub(pos).Truncate(DroneMovement))
+}
+
+// DroneMoveTo returns where a drone at pos ends its turn heading for target.
+func DroneMoveTo(pos, target Vec2) Vec2 {
+	return snapToMap(pos.Add(DroneMoveSpeed(pos, target)))
+}
+
+// DroneWaitAt returns where a drone at pos ends its turn after a WAIT, sinking without moving sideways.
+func DroneWaitAt(pos Vec2) Vec2 {
+	return snapToMap(pos.Add(V(0, DroneSinkSpeed)))
+}
+
+// DroneEmergencyAt returns where a drone in emergency at pos ends its turn, floating up on its own.
+func DroneEmergencyAt(pos Vec2) Vec2 {
+	return snapToMap(pos.Add(V(0, -DroneEmergencySpeed)))
+}
+
+// CreatureSpeed returns the speed of a creature going along direction at the given speed, zero if the
+// direction is zero.
+func CreatureSpeed(direction Vec2, speed float64) Vec2 {
+	return engineRound(direction.WithLength(speed))
+}
+
+// BounceSpeed returns the speed of a creature at pos reversed on each axis it would leave its habitat
+// along, fleeing fish swim off the sides of the map instead of bouncing.
+func BounceSpeed(creatureType CreatureType, pos, speed Vec2, fleeing bool) Vec2 {
+	minY, maxY := creatureDepths(creatureType)
+	next := pos.Add(speed)
+	if (next.X < 0 || next.X > MapMax) && !fleeing {
+		speed.X = -speed.X
+	}
+	if next.Y < float64(minY) || next.Y > float64(min(maxY, MapMax)) {
+		speed.Y = -speed.Y
+	}
+	return speed
+}
+
+// CreatureMoveTo returns where a creature at pos ends its turn and false if a fleeing fish left the map.
+func CreatureMoveTo(creatureType CreatureType, pos, speed Vec2, fleeing bool) (Vec2, bool) {
+	next := pos.Add(speed)
+	if creatureType != Monster && fleeing && (next.X < 0 || next.X > MapMax) {
+		return next, false
+	}
+	minY, maxY := creatureDepths(creatureType)
+	next.X = math.Max(0, math.Min(MapMax, next.X))
+	next.Y = math.Max(float64(minY), math.Min(float64(min(maxY, MapMax)), next.Y))
+	return next, true
+}
+
+// snapToMap keeps a drone position within the map.
+func snapToMap(pos Vec2) Vec2 {
+	return Vec2{math.Max(0, math.Min(MapMax, pos.X)), math.Max(0, math.Min(MapMax, pos.Y))}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDroneMoveTo_EngineRounding(t *testing.T) {
+	tests := []struct {
+		from, target, want Vec2
+	}{
+		{V(3333, 500), V(6666, 5000), V(3690, 982)},
+		{V(5000, 5000), V(4999, 4998), V(4999, 4998)},
+		{V(5000, 5000), V(4000, 3000), V(4732, 4463)},
+		{V(9800, 9900), V(10500, 10500), V(MapMax, MapMax)},
+	}
+	for _, test := range tests {
+		if got := DroneMoveTo(test.from, test.target); got != test.want {
+			t.Errorf("Expected %v moving from %v to %v, got %v", test.want, test.from, test.target, got)
+		}
+	}
+}
+
+func TestDroneWaitAndEmergency(t *testing.T) {
+	if got := DroneWaitAt(V(5000, 9900)); got != V(5000, MapMax) {
+		t.Errorf("Expected to sink to the bottom, got %v", got)
+	}
+	if got := DroneEmergencyAt(V(5000, 200)); got != V(5000, 0) {
+		t.Errorf("Expected to float up to the surface, got %v", got)
+	}
+}
+
+func TestEngineRound_SnapsNearHalves(t *testing.T) {
+	if got := engineRound(Vec2{2.49999999999, -0.5}); got != V(3, 0) {
+		t.Errorf("Expected 3,0, got %v", got)
+	}
+}
+
+func TestCreatureSpeedAndBounce(t *testing.T) {
+	if got := CreatureSpeed(V(1, 1), FishSpeed); got != V(141, 141) {
+		t.Errorf("Expected 141,141, got %v", got)
+	}
+	if got := CreatureSpeed(V(300, 400), MonsterAttackSpeed); got != V(324, 432) {
+		t.Errorf("Expected 324,432, got %v", got)
+	}
+	if got := BounceSpeed(MediumFish, V(5000, 7450), V(120, 160), false); got != V(120, -160) {
+		t.Errorf("Expected to bounce off the bottom of the habitat, got %v", got)
+	}
+	if got := BounceSpeed(ShallowFish, V(100, 4000), V(-400, 0), true); got != V(-400, 0) {
+		t.Errorf("Expected a fleeing fish to keep going, got %v", got)
+	}
+	if _, onMap := CreatureMoveTo(ShallowFish, V(100, 4000), V(-400, 0), true); onMap {
+		t.Errorf("Expected the fleeing fish to leave the map")
+	}
+}
+
+// movementFixture is one turn of a game: the input of the turn, the commands of my drones and the input of
+// the next turn, which holds where the creatures moved and the speeds the engine gave them.
+type movementFixture struct {
+	Source   string    `json:"source"`
+	Init     string    `json:"init"`
+	Turns    [2]string `json:"turns"`
+	Commands []string  `json:"commands"`
+}
+
+// Creatures seen on a turn are moved by MoveAll to where the next turn shows them, with the same speeds.
+// The drones are put where the next turn shows them, as the engine updates speeds after they moved.
+func TestMoveAll_MatchesFixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "movement", "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Expected movement fixtures, got %v", err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fixture movementFixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		state, next := NewGameState(), NewGameState()
+		for i, game := range []*GameState{state, next} {
+			if err := game.ReadInit(strings.NewReader(fixture.Init)); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+			if err := game.ReadTurn(strings.NewReader(fixture.Turns[i])); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+		}
+		state.NextTurn()
+		state.TrackFoeBehavior()
+		for i, command := range fixture.Commands {
+			if strings.HasSuffix(command, " 1") {
+				state.MyDrones[i].LastLightTurn = state.Turn
+			}
+		}
+		for _, drone := range next.MyDrones {
+			state.UpdateMyDrone(drone.Id, int(drone.Pos.X), int(drone.Pos.Y), drone.Emergency, drone.Battery)
+		}
+		for _, drone := range next.FoeDrones {
+			state.UpdateFoeDrone(drone.Id, int(drone.Pos.X), int(drone.Pos.Y), drone.Emergency, drone.Battery)
+		}
+		state.TrackFoeBehavior()
+		state.MoveAll()
+
+		for _, seen := range next.Creatures {
+			predicted := state.GetCreature(seen.Id)
+			if seen.LastVisibleTurn == NotInitialized || predicted.LastVisibleTurn == NotInitialized {
+				continue
+			}
+			if predicted.Pos != seen.Pos || predicted.Speed != seen.Speed {
+				t.Errorf("%s: expected creature %d at %v moving %v, predicted %v moving %v", path, seen.Id,
+					seen.Pos, seen.Speed, predicted.Pos, predicted.Speed)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+const (
+	PathDangerWeight  = 4.0 // extra cost of crossing a cell of full danger, relative to its length
+	PathBlockedDanger = 1.0 // danger of the cells a path may not enter
+)
+
+// Path is a route of the drone through the danger field, one point per turn.
+type Path struct {
+	Points []Vec2
+	Turns  int
+	Danger float64 // highest danger met along the way
+}
+
+// PlanPath searches the danger grid with A* for the cheapest route from the drone to goal, weighing the
+// distance travelled with the danger of each cell on the turn the drone gets there. Cells within the hit
+// radius of a monster are avoided, it returns false if the drone is boxed in.
+func (drone *Drone) PlanPath(state *GameState, goal Vec2) (Path, bool) {
+	field := state.Danger()
+	startCol, startRow := field.CellOf(drone.Pos)
+	goalCol, goalRow := field.CellOf(goal)
+	start, goalCell := startRow*field.Size+startCol, goalRow*field.Size+goalCol
+
+	cost := make([]float64, field.Size*field.Size)
+	for cell := range cost {
+		cost[cell] = math.Inf(1)
+	}
+	cost[start] = 0
+	previous := make([]int, field.Size*field.Size)
+	closed := make([]bool, field.Size*field.Size)
+	open := &pathQueue{}
+	heap.Push(open, pathNode{cell: start, priority: field.cellDistance(start, goalCell)})
+	for open.Len() > 0 {
+		node := heap.Pop(open).(pathNode)
+		if closed[node.cell] {
+			continue
+		}
+		closed[node.cell] = true
+		if node.cell == goalCell {
+			return drone.pathThrough(state, field.cellRoute(previous, start, goalCell), goal), true
+		}
+		col, row := node.cell%field.Size, node.cell/field.Size
+		for _, step := range [][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+			nextCol, nextRow := col+step[0], row+step[1]
+			if nextCol < 0 || nextRow < 0 || nextCol >= field.Size || nextRow >= field.Size {
+				continue
+			}
+			next := nextRow*field.Size + nextCol
+			length := field.cellDistance(node.cell, next)
+			turn := 1 + int((cost[node.cell]+length)/DroneMovement)
+			danger := field.Cell(nextCol, nextRow, turn)
+			if danger >= PathBlockedDanger && next != goalCell {
+				continue
+			}
+			nextCost := cost[node.cell] + length*(1+PathDangerWeight*danger)
+			if cost[next] <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			previous[next] = node.cell
+			heap.Push(open, pathNode{cell: next, priority: nextCost + field.cellDistance(next, goalCell)})
+		}
+	}
+	return Path{}, false
+}
+
+// cellDistance returns the distance between the centers of two cells.
+func (field *DangerField) cellDistance(a, b int) float64 {
+	return field.CellCenter(a%field.Size, a/field.Size).Dist(field.CellCenter(b%field.Size, b/field.Size))
+}
+
+// cellRoute returns the centers of the cells from start to goal following the search links.
+func (field *DangerField) cellRoute(previous []int, start, goal int) []Vec2 {
+	var route []Vec2
+	for cell := goal; cell != start; cell = previous[cell] {
+		route = append([]Vec2{field.CellCenter(cell%field.Size, cell/field.Size)}, route...)
+	}
+	return route
+}
+
+// pathThrough cuts the route from the drone through the cell centers to goal into moves of one turn and
+// rates them with the danger field.
+func (drone *Drone) pathThrough(state *GameState, route []Vec2, goal Vec2) Path {
+	if len(route) > 0 {
+		route = route[:len(route)-1]
+	}
+	route = state.Danger().smooth(append([]Vec2{drone.Pos}, append(route, goal)...))[1:]
+
+	var path Path
+	pos := drone.Pos
+	for len(route) > 0 {
+		// Walk one drone move along the route
+		left := float64(DroneMovement)
+		next := pos
+		for len(route) > 0 && left > 0 {
+			leg := route[0].Sub(next)
+			if leg.Length() > left {
+				next = next.Add(leg.WithLength(left))
+				break
+			}
+			left -= leg.Length()
+			next = route[0]
+			route = route[1:]
+		}
+		end := DroneMoveTo(pos, next)
+		path.Danger = math.Max(path.Danger, state.Danger().Segment(engineRound(pos), engineRound(end), len(path.Points)+1))
+		path.Points = append(path.Points, engineRound(end))
+		pos = end
+	}
+	path.Turns = len(path.Points)
+	return path
+}
+
+// smooth drops the points of the route that can be cut straight across without meeting more danger than
+// the corners they skip, so the drone does not zigzag from cell center to cell center.
+func (field *DangerField) smooth(route []Vec2) []Vec2 {
+	smoothed := []Vec2{route[0]}
+	travelled := 0.0
+	for anchor := 0; anchor < len(route)-1; {
+		next := anchor + 1
+		for candidate := len(route) - 1; candidate > anchor+1; candidate-- {
+			if field.isShortcut(route, anchor, candidate, travelled) {
+				next = candidate
+				break
+			}
+		}
+		travelled += route[anchor].Dist(route[next])
+		smoothed = append(smoothed, route[next])
+		anchor = next
+	}
+	return smoothed
+}
+
+// isShortcut returns true if going straight from route[from] to route[to] after travelling the given
+// distance meets no more danger than the points in between.
+func (field *DangerField) isShortcut(route []Vec2, from, to int, travelled float64) bool {
+	allowed := 0.0
+	for i := from + 1; i < to; i++ {
+		allowed = math.Max(allowed, field.Cell(field.cellColRow(route[i], travelled+route[from].Dist(route[i]))))
+	}
+	line := route[to].Sub(route[from])
+	steps := int(line.Length()/(DangerCellSize/2)) + 1
+	for step := 1; step < steps; step++ {
+		point := route[from].Add(line.Scale(float64(step) / float64(steps)))
+		danger := field.Cell(field.cellColRow(point, travelled+line.Length()*float64(step)/float64(steps)))
+		if danger > allowed || danger >= PathBlockedDanger {
+			return false
+		}
+	}
+	return true
+}
+
+// cellColRow returns the cell of pos and the turn a drone reaches it after travelling the given distance.
+func (field *DangerField) cellColRow(pos Vec2, travelled float64) (int, int, int) {
+	col, row := field.CellOf(pos)
+	return col, row, 1 + int(travelled/DroneMovement)
+}
+
+// String returns a short representation of the path for logging.
+func (path Path) String() string {
+	return fmt.Sprintf("Path{Turns: %d, Danger: %.2f, Points: %v}", path.Turns, path.Danger, path.Points)
+}
+
+// pathNode is a cell waiting to be expanded by the search.
+type pathNode struct {
+	cell     int
+	priority float64
+}
+
+// pathQueue is the open set of the search, cheapest estimated route first.
+type pathQueue []pathNode
+
+func (queue pathQueue) Len() int           { return len(queue) }
+func (queue pathQueue) Less(i, j int) bool { return queue[i].priority < queue[j].priority }
+func (queue pathQueue) Swap(i, j int)      { queue[i], queue[j] = queue[j], queue[i] }
+func (queue *pathQueue) Push(node any)     { *queue = append(*queue, node.(pathNode)) }
+func (queue *pathQueue) Pop() any {
+	old := *queue
+	node := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return node
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestPlanPath_GoesAroundMonster(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(16, 5000, 6000, 0, 0)
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+
+	path, ok := state.GetDrone(0).PlanPath(state, V(5000, 8000))
+	if !ok {
+		t.Fatalf("Expected a path around the monster")
+	}
+	if last := path.Points[len(path.Points)-1]; last != V(5000, 8000) {
+		t.Errorf("Expected the path to end at 5000,8000, got %v", last)
+	}
+	if path.Turns < 4 || path.Turns != len(path.Points) {
+		t.Errorf("Expected at least 4 turns, one point each, got %v", path)
+	}
+	for _, point := range path.Points {
+		if point.Dist(V(5000, 6000)) <= MonsterHitRadius {
+			t.Errorf("Expected path to stay away from the monster, got %v", point)
+		}
+	}
+	if path.Danger >= 1 {
+		t.Errorf("Expected a path out of reach of the monster, got %v", path)
+	}
+}
+
+func TestPlanPath_StraightWithoutMonsters(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 1000, 1000, 0, 30)
+
+	path, ok := state.GetDrone(0).PlanPath(state, V(1000, 2800))
+	if !ok || path.Turns != 3 || path.Danger != 0 {
+		t.Errorf("Expected 3 safe turns straight down, got %v", path)
+	}
+}
+
+func TestAvoidMonsters_FallsBackWhenBoxedIn(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 5000, 0, 30)
+	for i, pos := range [][2]int{{4600, 5000}, {5400, 5000}, {5000, 4600}, {5000, 5400}} {
+		state.AddCreature(NewCreature(16+i, -1, Monster))
+		state.UpdateCreature(16+i, pos[0], pos[1], 0, 0)
+	}
+	drone := state.GetDrone(0)
+
+	if _, ok := drone.PlanPath(state, V(5000, 9000)); ok {
+		t.Fatalf("Expected no path out of the monsters")
+	}
+	end := drone.AvoidMonsters(state, V(5000, 5600), V(5000, 9000))
+	if want := drone.CalculateBestPathToAvoidMonsters(state, V(5000, 5600)); end != want {
+		t.Errorf("Expected the five angle fallback %v, got %v", want, end)
+	}
+}
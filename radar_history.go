@@ -0,0 +1,88 @@
+package main
+
+import "fmt"
+
+// RadarRecord is a radar blip as it was received, with the position of the drone at the time.
+type RadarRecord struct {
+	Turn       int
+	DroneId    int
+	DronePos   Vec2
+	CreatureId int
+	Blip       RadarBlip
+}
+
+// RadarHistory keeps every radar blip of the game, indexed by creature and by turn.
+type RadarHistory struct {
+	Records    []RadarRecord
+	byCreature map[int][]int
+	byTurn     map[int][]int
+}
+
+// Add stores a record in the history.
+func (history *RadarHistory) Add(record RadarRecord) {
+	if history.byCreature == nil {
+		history.byCreature = make(map[int][]int)
+		history.byTurn = make(map[int][]int)
+	}
+	index := len(history.Records)
+	history.Records = append(history.Records, record)
+	history.byCreature[record.CreatureId] = append(history.byCreature[record.CreatureId], index)
+	history.byTurn[record.Turn] = append(history.byTurn[record.Turn], index)
+}
+
+// ByCreature returns the records of a creature, oldest first.
+func (history *RadarHistory) ByCreature(creatureId int) []RadarRecord {
+	return history.collect(history.byCreature[creatureId])
+}
+
+// ByTurn returns the records received on a turn.
+func (history *RadarHistory) ByTurn(turn int) []RadarRecord {
+	return history.collect(history.byTurn[turn])
+}
+
+func (history *RadarHistory) collect(indexes []int) []RadarRecord {
+	records := make([]RadarRecord, 0, len(indexes))
+	for _, index := range indexes {
+		records = append(records, history.Records[index])
+	}
+	return records
+}
+
+// Contradicts returns true if a creature at pos on the given turn cannot have produced the blip, allowing
+// it to have moved at most speed units per turn since.
+func (record RadarRecord) Contradicts(pos Vec2, turn, speed int) bool {
+	slack := float64(speed * max(0, turn-record.Turn))
+	drone := record.DronePos
+	switch record.Blip {
+	case TopLeft:
+		return pos.X > drone.X+slack || pos.Y > drone.Y+slack
+	case TopRight:
+		return pos.X < drone.X-slack || pos.Y > drone.Y+slack
+	case BottomLeft:
+		return pos.X > drone.X+slack || pos.Y < drone.Y-slack
+	case BottomRight:
+		return pos.X < drone.X-slack || pos.Y < drone.Y-slack
+	}
+	return false
+}
+
+// String returns a string representation of the record with field names.
+func (record RadarRecord) String() string {
+	return fmt.Sprintf("RadarRecord{Turn: %d, Drone: %d at %v, Creature: %d, Blip: %s}",
+		record.Turn, record.DroneId, record.DronePos, record.CreatureId, record.Blip)
+}
+
+// CheckConsistency returns the recorded blips the current estimate of the creature contradicts.
+func (state *GameState) CheckConsistency(creature *Creature) []RadarRecord {
+	speed := FishFleeSpeed
+	if creature.Type == Monster {
+		speed = MonsterAttackSpeed
+	}
+	var contradictions []RadarRecord
+	for _, record := range state.RadarHistory.ByCreature(creature.Id) {
+		if record.Contradicts(creature.Pos, state.Turn, speed) {
+			contradictions = append(contradictions, record)
+		}
+	}
+	return contradictions
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestRadarHistory_QueriesByCreatureAndTurn(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.UpdateMyDrone(0, 2500, 500, 0, 30)
+	state.UpdateRadarBlip(0, 4, string(BottomLeft))
+	state.UpdateRadarBlip(0, 5, string(BottomRight))
+	state.NextTurn()
+	state.PrepareForNextTurn()
+	state.UpdateMyDrone(0, 2500, 1100, 0, 30)
+	state.UpdateRadarBlip(0, 4, string(BottomRight))
+	state.NextTurn()
+
+	records := state.RadarHistory.ByCreature(4)
+	if len(records) != 2 || records[0].Turn != 1 || records[1].Turn != 2 || records[1].DronePos.Y != 1100 {
+		t.Errorf("Expected blips of turns 1 and 2 for creature 4, got %v", records)
+	}
+	if records := state.RadarHistory.ByTurn(1); len(records) != 2 {
+		t.Errorf("Expected 2 blips on turn 1, got %v", records)
+	}
+}
+
+func TestCheckConsistency_FlagsContradictedEstimate(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateMyDrone(0, 2500, 500, 0, 30)
+	state.UpdateRadarBlip(0, 4, string(BottomLeft))
+	state.NextTurn()
+	creature := state.GetCreature(4)
+
+	creature.Pos = V(2000, 3000)
+	if contradictions := state.CheckConsistency(creature); len(contradictions) != 0 {
+		t.Errorf("Expected estimate to agree with the blip, got %v", contradictions)
+	}
+
+	creature.Pos.X = 3500
+	if contradictions := state.CheckConsistency(creature); len(contradictions) != 1 {
+		t.Errorf("Expected estimate right of the drone to contradict the blip, got %v", contradictions)
+	}
+
+	// Two turns later the fish may have crossed over
+	state.Turn += 2
+	creature.Pos.X = 2900
+	if contradictions := state.CheckConsistency(creature); len(contradictions) != 0 {
+		t.Errorf("Expected estimate within reach of the blip, got %v", contradictions)
+	}
+}
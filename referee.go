@@ -0,0 +1,721 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	MapSize                = 10000
+	MaxTurns               = 200
+	SurfaceDepth           = 500
+	DroneSinkSpeed         = 300
+	DroneEmergencySpeed    = 300
+	DroneMaxBattery        = 30
+	LightBatteryCost       = 5
+	DroneBatteryRegen      = 1
+	ScanRadius             = 800
+	LightScanRadius        = 2000
+	FishHearingRadius      = 1400
+	FishFleeSpeed          = 400
+	MonsterSpeed           = 270
+	MonsterAttackSpeed     = 540
+	MonsterHitRadius       = 500
+	MonsterMinDepth        = 2500
+	MonsterVisibilityBonus = 300
+	FirstCreatureId        = 4
+	FishPairs              = 6
+	ColorComboPoints       = 3
+	TypeComboPoints        = 4
+)
+
+// refCreature is a creature as the referee knows it, with its true position.
+type refCreature struct {
+	Id       int          `json:"id"`
+	Color    int          `json:"color"`
+	Type     CreatureType `json:"type"`
+	X        int          `json:"x"`
+	Y        int          `json:"y"`
+	Vx       int          `json:"vx"`
+	Vy       int          `json:"vy"`
+	Fleeing  bool         `json:"fleeing"`
+	ScaredBy int          `json:"scaredBy"` // drone the fish last fled from
+	Lost     bool         `json:"lost"`
+}
+
+// refDrone is a drone as the referee knows it.
+type refDrone struct {
+	Id        int   `json:"id"`
+	X         int   `json:"x"`
+	Y         int   `json:"y"`
+	PrevX     int   `json:"prevX"`
+	PrevY     int   `json:"prevY"`
+	Emergency bool  `json:"emergency"`
+	Battery   int   `json:"battery"`
+	Light     bool  `json:"light"`
+	Scans     []int `json:"scans"`
+}
+
+// refPlayer holds everything the referee tracks for one side of the match.
+type refPlayer struct {
+	Drones   []*refDrone
+	Saved    []int
+	SaveTurn map[int]int
+	Score    int
+	Failure  string
+}
+
+// Referee simulates a match between two players locally, speaking the same protocol as the game.
+type Referee struct {
+	Turn      int
+	Creatures []*refCreature
+	Players   [2]*refPlayer
+	Events    []MatchEvent
+	rng       *rand.Rand
+}
+
+// EventKind is what happened in a MatchEvent.
+type EventKind string
+
+const (
+	EventLight EventKind = "light" // a drone used its light
+	EventScan  EventKind = "scan"  // a drone scanned a fish
+	EventSave  EventKind = "save"  // a drone brought a scan to the surface
+	EventHit   EventKind = "hit"   // a monster hit a drone, its scans are lost
+	EventLost  EventKind = "lost"  // a fleeing fish left the map
+)
+
+// MatchEvent is something that happened to a drone or creature during a turn, kept for match statistics.
+type MatchEvent struct {
+	Turn     int       `json:"turn"`
+	Kind     EventKind `json:"kind"`
+	Player   int       `json:"player"`   // owner of the drone, -1 if none
+	Drone    int       `json:"drone"`    // drone involved, -1 if none
+	Creature int       `json:"creature"` // fish scanned, saved or lost, monster of a hit
+	Distance int       `json:"distance,omitempty"`
+	Light    bool      `json:"light,omitempty"`
+	First    bool      `json:"first,omitempty"` // the fish was saved no later than by the foe, earning the bonus
+	Final    bool      `json:"final,omitempty"` // the save was made by the end of the game, not by a dive
+	Scans    int       `json:"scans,omitempty"` // scans lost in a hit
+}
+
+// ScoreBreakdown splits a score into the points of saved fish and of completed sets, each with the bonus
+// of being first.
+type ScoreBreakdown struct {
+	Base       int `json:"base"`
+	FirstSave  int `json:"firstSave"`
+	Combo      int `json:"combo"`
+	FirstCombo int `json:"firstCombo"`
+}
+
+// MatchResult is the outcome of a finished match from the point of view of player 0.
+type MatchResult struct {
+	Seed     int64     `json:"seed"`
+	Names    [2]string `json:"names"`
+	Scores   [2]int    `json:"scores"`
+	Failures [2]string `json:"failures"`
+	Turns    int       `json:"turns"`
+}
+
+// NewReferee returns a referee with a mirrored creature layout generated from the seed.
+func NewReferee(seed int64) *Referee {
+	referee := &Referee{rng: rand.New(rand.NewSource(seed))}
+	id := FirstCreatureId
+
+	// Fish come in mirrored pairs of the same type, each color and type combination exists once
+	for pair := 0; pair < FishPairs; pair++ {
+		fishType := CreatureType(pair / 2)
+		color := (pair % 2) * 2
+		minY, maxY := fishDepthsByType[fishType][0], fishDepthsByType[fishType][1]
+		x := 500 + referee.rng.Intn(MapSize/2-1000)
+		y := minY + 250 + referee.rng.Intn(maxY-minY-500)
+		angle := referee.rng.Float64() * 2 * math.Pi
+		vx, vy := Vec2{FishSpeed * math.Cos(angle), FishSpeed * math.Sin(angle)}.Ints()
+		referee.Creatures = append(referee.Creatures,
+			&refCreature{Id: id, Color: color, Type: fishType, X: x, Y: y, Vx: vx, Vy: vy},
+			&refCreature{Id: id + 1, Color: color + 1, Type: fishType, X: MapSize - x, Y: y, Vx: -vx, Vy: vy},
+		)
+		id += 2
+	}
+
+	// Monsters are mirrored as well and stay idle until they notice a drone
+	monsterPairs := 1 + referee.rng.Intn(3)
+	for pair := 0; pair < monsterPairs; pair++ {
+		x := 500 + referee.rng.Intn(MapSize/2-1000)
+		y := MediumFishMinDepth + referee.rng.Intn(MapSize-MediumFishMinDepth-500)
+		referee.Creatures = append(referee.Creatures,
+			&refCreature{Id: id, Color: -1, Type: Monster, X: x, Y: y},
+			&refCreature{Id: id + 1, Color: -1, Type: Monster, X: MapSize - x, Y: y},
+		)
+		id += 2
+	}
+
+	for p := range referee.Players {
+		referee.Players[p] = &refPlayer{SaveTurn: make(map[int]int)}
+	}
+	referee.Players[0].Drones = []*refDrone{
+		{Id: 0, X: 3333, Y: SurfaceDepth, Battery: DroneMaxBattery},
+		{Id: 2, X: 6666, Y: SurfaceDepth, Battery: DroneMaxBattery},
+	}
+	referee.Players[1].Drones = []*refDrone{
+		{Id: 1, X: 6666, Y: SurfaceDepth, Battery: DroneMaxBattery},
+		{Id: 3, X: 3333, Y: SurfaceDepth, Battery: DroneMaxBattery},
+	}
+	return referee
+}
+
+// InitInput returns the initialization input of the given player.
+func (referee *Referee) InitInput(player int) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, len(referee.Creatures))
+	for _, creature := range referee.Creatures {
+		fmt.Fprintln(&b, creature.Id, creature.Color, int(creature.Type))
+	}
+	return b.String()
+}
+
+// TurnInput returns the input of the current turn for the given player.
+func (referee *Referee) TurnInput(player int) string {
+	var b strings.Builder
+	me, foe := referee.Players[player], referee.Players[1-player]
+	fmt.Fprintln(&b, me.Score)
+	fmt.Fprintln(&b, foe.Score)
+	for _, side := range []*refPlayer{me, foe} {
+		fmt.Fprintln(&b, len(side.Saved))
+		for _, id := range side.Saved {
+			fmt.Fprintln(&b, id)
+		}
+	}
+	for _, side := range []*refPlayer{me, foe} {
+		fmt.Fprintln(&b, len(side.Drones))
+		for _, drone := range side.Drones {
+			fmt.Fprintln(&b, drone.Id, drone.X, drone.Y, boolInt(drone.Emergency), drone.Battery)
+		}
+	}
+
+	var scans []string
+	for _, side := range []*refPlayer{me, foe} {
+		for _, drone := range side.Drones {
+			for _, id := range drone.Scans {
+				scans = append(scans, fmt.Sprint(drone.Id, id))
+			}
+		}
+	}
+	fmt.Fprintln(&b, len(scans))
+	for _, scan := range scans {
+		fmt.Fprintln(&b, scan)
+	}
+
+	visible := referee.visibleCreatures(me)
+	fmt.Fprintln(&b, len(visible))
+	for _, creature := range visible {
+		fmt.Fprintln(&b, creature.Id, creature.X, creature.Y, creature.Vx, creature.Vy)
+	}
+
+	var blips []string
+	for _, drone := range me.Drones {
+		for _, creature := range referee.Creatures {
+			if creature.Lost {
+				continue
+			}
+			blips = append(blips, fmt.Sprint(drone.Id, creature.Id, quadrant(drone.X, drone.Y, creature.X, creature.Y)))
+		}
+	}
+	fmt.Fprintln(&b, len(blips))
+	for _, blip := range blips {
+		fmt.Fprintln(&b, blip)
+	}
+	return b.String()
+}
+
+// visibleCreatures returns creatures within the lighted area of any of the player's drones.
+func (referee *Referee) visibleCreatures(player *refPlayer) []*refCreature {
+	var visible []*refCreature
+	for _, creature := range referee.Creatures {
+		if creature.Lost {
+			continue
+		}
+		for _, drone := range player.Drones {
+			radius := drone.scanRadius()
+			if creature.Type == Monster {
+				radius += MonsterVisibilityBonus
+			}
+			if distance(drone.X, drone.Y, creature.X, creature.Y) <= radius {
+				visible = append(visible, creature)
+				break
+			}
+		}
+	}
+	return visible
+}
+
+// Step plays one turn with the commands of both players, one line per drone.
+func (referee *Referee) Step(commands [2][]string) {
+	referee.Turn++
+	for p, player := range referee.Players {
+		for i, drone := range player.Drones {
+			command := "WAIT 0"
+			if i < len(commands[p]) {
+				command = commands[p][i]
+			}
+			if err := drone.apply(command); err != nil && player.Failure == "" {
+				player.Failure = err.Error()
+			}
+			if drone.Light {
+				referee.event(MatchEvent{Kind: EventLight, Player: p, Drone: drone.Id, Creature: -1})
+			}
+		}
+	}
+	referee.resolveMonsterHits()
+	referee.moveCreatures()
+	referee.scan()
+	referee.save()
+	referee.updateCreatureSpeeds()
+	referee.updateScores()
+}
+
+// Over returns true when the match cannot continue or nothing is left to score.
+func (referee *Referee) Over() bool {
+	if referee.Turn >= MaxTurns {
+		return true
+	}
+	for _, player := range referee.Players {
+		if player.Failure != "" {
+			return true
+		}
+	}
+	for _, creature := range referee.Creatures {
+		if creature.Type == Monster {
+			continue
+		}
+		for _, player := range referee.Players {
+			if _, saved := player.SaveTurn[creature.Id]; saved {
+				continue
+			}
+			if !creature.Lost || player.hasScan(creature.Id) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Finish saves the scans still held by drones that are not in emergency and computes final scores.
+func (referee *Referee) Finish() {
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if !drone.Emergency {
+				referee.saveDrone(p, drone, referee.Turn+1, true)
+			}
+		}
+	}
+	referee.updateScores()
+}
+
+// Result returns the result of the match, meaningful once it is over.
+func (referee *Referee) Result(seed int64, names [2]string) MatchResult {
+	return MatchResult{
+		Seed:     seed,
+		Names:    names,
+		Scores:   [2]int{referee.Players[0].Score, referee.Players[1].Score},
+		Failures: [2]string{referee.Players[0].Failure, referee.Players[1].Failure},
+		Turns:    referee.Turn,
+	}
+}
+
+// Outcome returns 1 if player 0 won, 0.5 for a draw and 0 if player 0 lost, a failing player always loses.
+func (result MatchResult) Outcome() float64 {
+	failed0, failed1 := result.Failures[0] != "", result.Failures[1] != ""
+	switch {
+	case failed0 && !failed1:
+		return 0
+	case failed1 && !failed0:
+		return 1
+	case result.Scores[0] > result.Scores[1]:
+		return 1
+	case result.Scores[0] < result.Scores[1]:
+		return 0
+	}
+	return 0.5
+}
+
+// apply executes a MOVE or WAIT command, drones in emergency ignore the command and float up.
+func (drone *refDrone) apply(command string) error {
+	drone.PrevX, drone.PrevY = drone.X, drone.Y
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return fmt.Errorf("empty command for drone %d", drone.Id)
+	}
+
+	var x, y, light int
+	var err error
+	switch {
+	case fields[0] == "MOVE" && len(fields) >= 4:
+		if x, err = strconv.Atoi(fields[1]); err == nil {
+			if y, err = strconv.Atoi(fields[2]); err == nil {
+				light, err = strconv.Atoi(fields[3])
+			}
+		}
+	case fields[0] == "WAIT" && len(fields) >= 2:
+		light, err = strconv.Atoi(fields[1])
+	default:
+		err = fmt.Errorf("invalid command %q", command)
+	}
+	if err != nil {
+		return fmt.Errorf("drone %d: %v", drone.Id, err)
+	}
+
+	if drone.Emergency {
+		drone.Light = false
+		drone.Battery = min(DroneMaxBattery, drone.Battery+DroneBatteryRegen)
+		drone.X, drone.Y = DroneEmergencyAt(V(drone.X, drone.Y)).Ints()
+		if drone.Y <= SurfaceDepth {
+			drone.Emergency = false
+		}
+		return nil
+	}
+
+	drone.Light = light == 1 && drone.Battery >= LightBatteryCost
+	if drone.Light {
+		drone.Battery -= LightBatteryCost
+	} else {
+		drone.Battery = min(DroneMaxBattery, drone.Battery+DroneBatteryRegen)
+	}
+
+	if fields[0] == "WAIT" {
+		drone.X, drone.Y = DroneWaitAt(V(drone.X, drone.Y)).Ints()
+	} else {
+		drone.X, drone.Y = DroneMoveTo(V(drone.X, drone.Y), V(x, y)).Ints()
+	}
+	return nil
+}
+
+// scanRadius returns the radius in which the drone scans creatures this turn.
+func (drone *refDrone) scanRadius() int {
+	if drone.Light {
+		return LightScanRadius
+	}
+	return ScanRadius
+}
+
+// resolveMonsterHits puts drones that got too close to a monster during their move into emergency.
+func (referee *Referee) resolveMonsterHits() {
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if drone.Emergency {
+				continue
+			}
+			for _, monster := range referee.Creatures {
+				if monster.Type != Monster || monster.Lost {
+					continue
+				}
+				// Move the drone relative to the monster and find the closest approach
+				start := V(drone.PrevX, drone.PrevY).Sub(V(monster.X, monster.Y))
+				end := start.Add(V(drone.X-drone.PrevX-monster.Vx, drone.Y-drone.PrevY-monster.Vy))
+				if (Vec2{}).ClosestOnSegment(start, end).Length() <= MonsterHitRadius {
+					referee.event(MatchEvent{Kind: EventHit, Player: p, Drone: drone.Id, Creature: monster.Id, Scans: len(drone.Scans)})
+					drone.Emergency = true
+					drone.Light = false
+					drone.Scans = nil
+					break
+				}
+			}
+		}
+	}
+}
+
+// moveCreatures applies creature speeds, fish fleeing across the map border are lost.
+func (referee *Referee) moveCreatures() {
+	for _, creature := range referee.Creatures {
+		if creature.Lost {
+			continue
+		}
+		next, onMap := CreatureMoveTo(creature.Type, V(creature.X, creature.Y), V(creature.Vx, creature.Vy), creature.Fleeing)
+		creature.X, creature.Y = next.Ints()
+		creature.Lost = !onMap
+		if creature.Lost {
+			referee.event(MatchEvent{Kind: EventLost, Player: referee.owner(creature.ScaredBy), Drone: creature.ScaredBy, Creature: creature.Id})
+		}
+	}
+}
+
+// scan adds the fish within the scan radius of each drone to its scans.
+func (referee *Referee) scan() {
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if drone.Emergency {
+				continue
+			}
+			for _, creature := range referee.Creatures {
+				if creature.Type == Monster || creature.Lost {
+					continue
+				}
+				if _, saved := player.SaveTurn[creature.Id]; saved || player.hasScan(creature.Id) {
+					continue
+				}
+				if dist := distance(drone.X, drone.Y, creature.X, creature.Y); dist <= drone.scanRadius() {
+					drone.Scans = append(drone.Scans, creature.Id)
+					referee.event(MatchEvent{Kind: EventScan, Player: p, Drone: drone.Id, Creature: creature.Id, Distance: dist, Light: drone.Light})
+				}
+			}
+		}
+	}
+}
+
+// save stores the scans of drones that reached the surface.
+func (referee *Referee) save() {
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if !drone.Emergency && drone.Y <= SurfaceDepth {
+				referee.saveDrone(p, drone, referee.Turn, false)
+			}
+		}
+	}
+}
+
+// saveDrone saves the scans of a drone of player p on the given turn, final when the game ends.
+func (referee *Referee) saveDrone(p int, drone *refDrone, turn int, final bool) {
+	player, foe := referee.Players[p], referee.Players[1-p]
+	for _, id := range drone.Scans {
+		_, saved := player.SaveTurn[id]
+		foeTurn, foeSaved := foe.SaveTurn[id]
+		first := !saved && (!foeSaved || foeTurn == turn)
+		referee.Events = append(referee.Events, MatchEvent{Turn: turn, Kind: EventSave, Player: p, Drone: drone.Id, Creature: id, First: first, Final: final})
+	}
+	player.save(drone, turn)
+}
+
+// event records what happened on the current turn.
+func (referee *Referee) event(event MatchEvent) {
+	event.Turn = referee.Turn
+	referee.Events = append(referee.Events, event)
+}
+
+// owner returns the player of the drone, -1 if there is no such drone.
+func (referee *Referee) owner(droneId int) int {
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if drone.Id == droneId {
+				return p
+			}
+		}
+	}
+	return -1
+}
+
+// save moves the scans of the drone to the saved scans of the player.
+func (player *refPlayer) save(drone *refDrone, turn int) {
+	for _, id := range drone.Scans {
+		if _, saved := player.SaveTurn[id]; !saved {
+			player.SaveTurn[id] = turn
+			player.Saved = append(player.Saved, id)
+		}
+	}
+	drone.Scans = nil
+}
+
+// hasScan returns true if any drone of the player holds an unsaved scan of the creature.
+func (player *refPlayer) hasScan(creatureId int) bool {
+	for _, drone := range player.Drones {
+		for _, id := range drone.Scans {
+			if id == creatureId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updateCreatureSpeeds decides creature speeds for the next turn.
+func (referee *Referee) updateCreatureSpeeds() {
+	for _, creature := range referee.Creatures {
+		if creature.Lost {
+			continue
+		}
+		if creature.Type == Monster {
+			referee.updateMonsterSpeed(creature)
+		} else {
+			referee.updateFishSpeed(creature)
+		}
+		speed := BounceSpeed(creature.Type, V(creature.X, creature.Y), V(creature.Vx, creature.Vy), creature.Fleeing)
+		creature.Vx, creature.Vy = speed.Ints()
+	}
+}
+
+// updateFishSpeed makes fish flee from close drones, avoid other fish or keep swimming.
+func (referee *Referee) updateFishSpeed(fish *refCreature) {
+	if drone, dist := referee.closestDrone(fish.X, fish.Y); drone != nil && dist <= FishHearingRadius {
+		fish.Vx, fish.Vy = scaleTowards(fish.X-drone.X, fish.Y-drone.Y, FishFleeSpeed)
+		fish.Fleeing, fish.ScaredBy = true, drone.Id
+		return
+	}
+	fish.Fleeing = false
+	for _, other := range referee.Creatures {
+		if other == fish || other.Lost || other.Type == Monster {
+			continue
+		}
+		if distance(fish.X, fish.Y, other.X, other.Y) <= FishCollision {
+			fish.Vx, fish.Vy = scaleTowards(fish.X-other.X, fish.Y-other.Y, FishSpeed)
+			return
+		}
+	}
+	fish.Vx, fish.Vy = scaleTowards(fish.Vx, fish.Vy, FishSpeed)
+}
+
+// updateMonsterSpeed makes monsters chase drones they see or keep searching at low speed.
+func (referee *Referee) updateMonsterSpeed(monster *refCreature) {
+	var target *refDrone
+	targetDistance := math.MaxInt32
+	for _, player := range referee.Players {
+		for _, drone := range player.Drones {
+			dist := distance(drone.X, drone.Y, monster.X, monster.Y)
+			if !drone.Emergency && dist <= drone.scanRadius() && dist < targetDistance {
+				target, targetDistance = drone, dist
+			}
+		}
+	}
+	if target != nil {
+		monster.Vx, monster.Vy = scaleTowards(target.X-monster.X, target.Y-monster.Y, MonsterAttackSpeed)
+		return
+	}
+	for _, other := range referee.Creatures {
+		if other != monster && other.Type == Monster && distance(monster.X, monster.Y, other.X, other.Y) <= FishCollision {
+			monster.Vx, monster.Vy = scaleTowards(monster.X-other.X, monster.Y-other.Y, MonsterSpeed)
+			return
+		}
+	}
+	monster.Vx, monster.Vy = scaleTowards(monster.Vx, monster.Vy, MonsterSpeed)
+}
+
+// closestDrone returns the closest drone to the point that is not in emergency.
+func (referee *Referee) closestDrone(x, y int) (*refDrone, int) {
+	var closest *refDrone
+	closestDistance := math.MaxInt32
+	for _, player := range referee.Players {
+		for _, drone := range player.Drones {
+			if drone.Emergency {
+				continue
+			}
+			if dist := distance(x, y, drone.X, drone.Y); dist < closestDistance {
+				closest, closestDistance = drone, dist
+			}
+		}
+	}
+	return closest, closestDistance
+}
+
+// updateScores recomputes the score of both players from the turns their scans were saved.
+func (referee *Referee) updateScores() {
+	for p, player := range referee.Players {
+		player.Score = referee.score(p)
+	}
+}
+
+// score returns the points of a player, a save or combo is doubled when the foe did not achieve it earlier.
+func (referee *Referee) score(p int) int {
+	return referee.Breakdown(p).Total()
+}
+
+// Breakdown returns the points of a player by where they come from.
+func (referee *Referee) Breakdown(p int) ScoreBreakdown {
+	me, foe := referee.Players[p], referee.Players[1-p]
+	var breakdown ScoreBreakdown
+	for id, turn := range me.SaveTurn {
+		points := getScanPoints(referee.creature(id).Type, false)
+		breakdown.Base += points
+		if foeTurn, ok := foe.SaveTurn[id]; !ok || turn <= foeTurn {
+			breakdown.FirstSave += points
+		}
+	}
+
+	colors := map[int][]int{}
+	types := map[CreatureType][]int{}
+	for _, creature := range referee.Creatures {
+		if creature.Type != Monster {
+			colors[creature.Color] = append(colors[creature.Color], creature.Id)
+			types[creature.Type] = append(types[creature.Type], creature.Id)
+		}
+	}
+	for _, ids := range colors {
+		breakdown.addCombo(me, foe, ids, ColorComboPoints)
+	}
+	for _, ids := range types {
+		breakdown.addCombo(me, foe, ids, TypeComboPoints)
+	}
+	return breakdown
+}
+
+// Total returns the score.
+func (breakdown ScoreBreakdown) Total() int {
+	return breakdown.Base + breakdown.FirstSave + breakdown.Combo + breakdown.FirstCombo
+}
+
+// addCombo adds the bonus of a completed set of creatures, doubled if completed first.
+func (breakdown *ScoreBreakdown) addCombo(me, foe *refPlayer, ids []int, points int) {
+	myTurn, ok := completionTurn(me, ids)
+	if !ok {
+		return
+	}
+	breakdown.Combo += points
+	if foeTurn, ok := completionTurn(foe, ids); !ok || myTurn <= foeTurn {
+		breakdown.FirstCombo += points
+	}
+}
+
+// completionTurn returns the turn the player saved the last creature of the set.
+func completionTurn(player *refPlayer, ids []int) (int, bool) {
+	last := 0
+	for _, id := range ids {
+		turn, ok := player.SaveTurn[id]
+		if !ok {
+			return 0, false
+		}
+		last = max(last, turn)
+	}
+	return last, true
+}
+
+// creature returns the referee creature with the given id.
+func (referee *Referee) creature(id int) *refCreature {
+	for _, creature := range referee.Creatures {
+		if creature.Id == id {
+			return creature
+		}
+	}
+	return nil
+}
+
+// creatureDepths returns the vertical bounds a creature can move within.
+func creatureDepths(creatureType CreatureType) (int, int) {
+	if creatureType == Monster {
+		return MonsterMinDepth, MapSize
+	}
+	return fishDepthsByType[creatureType][0], fishDepthsByType[creatureType][1]
+}
+
+// quadrant returns the radar blip of a creature at x2,y2 seen from a drone at x1,y1.
+func quadrant(x1, y1, x2, y2 int) RadarBlip {
+	vertical, horizontal := "B", "R"
+	if y2 < y1 {
+		vertical = "T"
+	}
+	if x2 < x1 {
+		horizontal = "L"
+	}
+	return RadarBlip(vertical + horizontal)
+}
+
+// scaleTowards returns the vector vx,vy scaled to the given length, zero vectors stay zero.
+func scaleTowards(vx, vy, length int) (int, int) {
+	return CreatureSpeed(V(vx, vy), float64(length)).Ints()
+}
+
+func boolInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
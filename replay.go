@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Replay is a recorded game: the truth of the referee on every turn and what each player was sent and
+// answered, enough to rebuild what a bot believed by feeding it the same input again.
+type Replay struct {
+	Result    MatchResult       `json:"result"`
+	Breakdown [2]ScoreBreakdown `json:"breakdown"`
+	Init      [2]string         `json:"init"`
+	Turns     []ReplayTurn      `json:"turns"`
+	Events    []MatchEvent      `json:"events"`
+}
+
+// ReplayTurn is the truth when the input of a turn was sent, with the input and the commands answered.
+type ReplayTurn struct {
+	Turn      int           `json:"turn"`
+	Creatures []refCreature `json:"creatures"`
+	Drones    [2][]refDrone `json:"drones"`
+	Saved     [2][]int      `json:"saved"`
+	Scores    [2]int        `json:"scores"`
+	Input     [2]string     `json:"input"`
+	Commands  [2][]string   `json:"commands"`
+}
+
+// NewReplay starts the replay of a match with the initialization input of both players.
+func NewReplay(referee *Referee) *Replay {
+	return &Replay{Init: [2]string{referee.InitInput(0), referee.InitInput(1)}}
+}
+
+// AddTurn records the truth of the referee before the turn is played, with the input of both players.
+func (replay *Replay) AddTurn(referee *Referee, input [2]string) *ReplayTurn {
+	turn := ReplayTurn{Turn: referee.Turn + 1, Input: input}
+	for _, creature := range referee.Creatures {
+		turn.Creatures = append(turn.Creatures, *creature)
+	}
+	for p, player := range referee.Players {
+		for _, drone := range player.Drones {
+			copied := *drone
+			copied.Scans = slices.Clone(drone.Scans)
+			turn.Drones[p] = append(turn.Drones[p], copied)
+		}
+		turn.Saved[p] = slices.Clone(player.Saved)
+		turn.Scores[p] = player.Score
+	}
+	replay.Turns = append(replay.Turns, turn)
+	return &replay.Turns[len(replay.Turns)-1]
+}
+
+// Finish records the result of the match with the events and the final score of both players.
+func (replay *Replay) Finish(referee *Referee, result MatchResult) {
+	replay.Result = result
+	replay.Events = referee.Events
+	replay.Breakdown = [2]ScoreBreakdown{referee.Breakdown(0), referee.Breakdown(1)}
+}
+
+// FileName returns the name the replay is saved under, unique per seed and sides.
+func (replay *Replay) FileName() string {
+	clean := func(name string) string {
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`/\:*?"<>| `, r) {
+				return '_'
+			}
+			return r
+		}, name)
+	}
+	return fmt.Sprintf("seed%d-%s-vs-%s.json", replay.Result.Seed, clean(replay.Result.Names[0]), clean(replay.Result.Names[1]))
+}
+
+// SaveReplay writes the replay as JSON into the directory.
+func SaveReplay(dir string, replay *Replay) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, replay.FileName()), data, 0o644)
+}
+
+// LoadReplay reads a replay saved by SaveReplay.
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(replay.Turns) == 0 {
+		return nil, fmt.Errorf("%s: no turns recorded", path)
+	}
+	return &replay, nil
+}
+
+// ReplayBot feeds the recorded input of one player to a strategy turn by turn, rebuilding what the bot
+// believed and decided. The bot is deterministic so the rebuilt state is the one it had during the game.
+type ReplayBot struct {
+	Replay   *Replay
+	Player   int
+	Strategy Strategy
+	State    *GameState
+	out      bytes.Buffer
+	next     int
+}
+
+// NewReplayBot returns a bot that has read the initialization input and is ready for the first turn.
+func NewReplayBot(replay *Replay, player int, strategy Strategy) (*ReplayBot, error) {
+	bot := &ReplayBot{Replay: replay, Player: player, Strategy: strategy, State: NewGameState()}
+	bot.State.Out = &bot.out
+	if err := bot.State.ReadInit(strings.NewReader(replay.Init[player])); err != nil {
+		return nil, fmt.Errorf("init input: %v", err)
+	}
+	return bot, nil
+}
+
+// Step plays the next recorded turn, leaving the state as it was when the commands were decided.
+// It returns io.EOF after the last turn.
+func (bot *ReplayBot) Step() error {
+	if bot.next >= len(bot.Replay.Turns) {
+		return io.EOF
+	}
+	if bot.next > 0 {
+		bot.State.MoveAll()
+	}
+	turn := bot.Replay.Turns[bot.next]
+	bot.State.PrepareForNextTurn()
+	if err := bot.State.ReadTurn(strings.NewReader(turn.Input[bot.Player])); err != nil {
+		return fmt.Errorf("turn %d input: %v", turn.Turn, err)
+	}
+	bot.out.Reset()
+	bot.State.PlayTurn(bot.Strategy)
+	bot.next++
+	return nil
+}
+
+// Turn returns the recorded turn last played.
+func (bot *ReplayBot) Turn() *ReplayTurn {
+	if bot.next == 0 {
+		return nil
+	}
+	return &bot.Replay.Turns[bot.next-1]
+}
+
+// Commands returns the commands the strategy wrote on the last turn played.
+func (bot *ReplayBot) Commands() []string {
+	return strings.Split(strings.TrimRight(bot.out.String(), "\n"), "\n")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordedMatch plays a short match of the default strategy against idle and returns its replay.
+func recordedMatch(t *testing.T) *Replay {
+	t.Helper()
+	previous := logWriter
+	logWriter = io.Discard
+	t.Cleanup(func() { logWriter = previous })
+	bots, err := parseArenaBots([]string{"builtin:default", "builtin:idle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, replay := playMatch(2, [2]arenaBot{bots[0], bots[1]}, 5*time.Second, false)
+	return replay
+}
+
+func TestReplay_SaveAndLoad(t *testing.T) {
+	replay := recordedMatch(t)
+	dir := t.TempDir()
+
+	if err := SaveReplay(dir, replay); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReplay(filepath.Join(dir, "seed2-default-vs-idle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Result != replay.Result || len(loaded.Turns) != len(replay.Turns) {
+		t.Fatalf("Expected the same result and turns, got %+v", loaded.Result)
+	}
+	first, last := loaded.Turns[0], loaded.Turns[len(loaded.Turns)-1]
+	if first.Turn != 1 || first.Input != replay.Turns[0].Input || !slices.Equal(first.Commands[0], replay.Turns[0].Commands[0]) {
+		t.Errorf("Expected the first turn to round trip, got %+v", first)
+	}
+	if len(last.Creatures) != len(replay.Turns[0].Creatures) || len(last.Drones[1]) != 2 {
+		t.Errorf("Expected the truth of every creature and drone, got %+v", last)
+	}
+}
+
+func TestLoadReplay_RejectsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte(`{"turns":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplay(path); err == nil {
+		t.Errorf("Expected a replay without turns to be rejected")
+	}
+}
+
+func TestReplayBot_RebuildsTheSameDecisions(t *testing.T) {
+	replay := recordedMatch(t)
+	bot, err := NewReplayBot(replay, 0, NewDefaultStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, turn := range replay.Turns {
+		if err := bot.Step(); err != nil {
+			t.Fatalf("Turn %d: %v", turn.Turn, err)
+		}
+		if bot.State.Turn != turn.Turn || !slices.Equal(bot.Commands(), turn.Commands[0]) {
+			t.Fatalf("Turn %d: expected commands %q, got %q on turn %d", turn.Turn, turn.Commands[0], bot.Commands(), bot.State.Turn)
+		}
+	}
+	if err := bot.Step(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected EOF after the last turn, got %v", err)
+	}
+}
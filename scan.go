@@ -0,0 +1,97 @@
+package main
+
+import "math"
+
+const (
+	ScanPredictionThreshold = 0.5 // probability from which a fish is counted as scanned by a move
+	SweepProgressWeight     = 2.0 // points a full move towards the target is worth when sweeping
+)
+
+var sweepAngles = []float64{-45, -30, -15, 0, 15, 30, 45}
+
+// ScanPrediction is a fish a move is expected to scan with the probability it is within the scan radius.
+type ScanPrediction struct {
+	Creature    *Creature
+	Probability float64
+}
+
+// PredictScans lists the fish not yet scanned or delivered that would be scanned when the drone ends
+// its turn at pos with the given light setting, based on the current estimates and their uncertainty.
+func (drone *Drone) PredictScans(state *GameState, pos Vec2, light bool) []ScanPrediction {
+	radius := ScanRadius
+	if light {
+		radius = LightScanRadius
+	}
+
+	var predictions []ScanPrediction
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster || creature.Dead || creature.IsScanned(state) || creature.IsDelivered(state) {
+			continue
+		}
+		if probability := creature.ScanProbability(pos, radius); probability >= ScanPredictionThreshold {
+			predictions = append(predictions, ScanPrediction{Creature: creature, Probability: probability})
+		}
+	}
+	return predictions
+}
+
+// ScanValue returns the expected points of the scans predicted for a move.
+func ScanValue(state *GameState, predictions []ScanPrediction) float64 {
+	value := 0.0
+	for _, prediction := range predictions {
+		value += prediction.Probability * float64(getScanPoints(prediction.Creature.Type, !prediction.Creature.IsDeliveredByFoe(state)))
+	}
+	return value
+}
+
+// ApproachPoint returns the point on the way to the creature where it is expected to be just inside the
+// scan radius, so the drone does not travel all the way to the center of the estimate.
+func (drone *Drone) ApproachPoint(creature *Creature) Vec2 {
+	bounds := creature.Bounds
+	margin := FishSpeed + max(bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY)/2
+	reach := max(0, ScanRadius-margin)
+
+	if drone.Pos.Dist(creature.Pos) <= float64(reach) {
+		// Estimate must be off as the creature would have been scanned, go look at the center
+		return creature.Pos
+	}
+	return engineRound(creature.Pos.Add(drone.Pos.Sub(creature.Pos).WithLength(float64(reach))))
+}
+
+// ChooseEndPoint picks where to end this turn on the way to the target, trading progress towards the
+// approach point of the target against sweeping other unscanned fish into the scan radius and, for a
+// poorly localized target, against triangulating with the radar.
+func (drone *Drone) ChooseEndPoint(state *GameState) Vec2 {
+	approach := drone.ApproachPoint(drone.Target)
+	straight := drone.GetNextPositionTowardsTarget(approach)
+	startDistance := drone.Pos.Dist(approach)
+	if startDistance == 0 {
+		return straight
+	}
+
+	// A poorly localized target is worth a detour that lets the radar cut down its estimate
+	angles := sweepAngles
+	triangulate := drone.Target.IsPoorlyLocalized()
+	if triangulate {
+		angles = append(append([]float64{}, sweepAngles...), triangulationAngles...)
+	}
+
+	best := straight
+	bestScore := math.Inf(-1)
+	for _, angle := range angles {
+		end := straight
+		if angle != 0 {
+			move := approach.Sub(drone.Pos).Rotate(angle).WithLength(DroneMovement)
+			end = engineRound(drone.Pos.Add(move).ClampToMap())
+		}
+		progress := (startDistance - end.Dist(approach)) / DroneMovement
+		score := ScanValue(state, drone.PredictScans(state, end, false)) + SweepProgressWeight*progress
+		if triangulate {
+			score += InformationGainWeight * state.InformationGain(end)
+		}
+		if score > bestScore {
+			bestScore, best = score, end
+		}
+	}
+	return best
+}
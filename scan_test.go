@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestPredictScans_ScanRadius(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 4000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.UpdateCreature(4, 5700, 4000, 0, 0)
+	state.UpdateCreature(5, 6500, 4000, 0, 0)
+	drone := state.GetDrone(0)
+
+	if scans := drone.PredictScans(state, V(5000, 4000), false); len(scans) != 1 || scans[0].Creature.Id != 4 {
+		t.Errorf("Expected only creature 4 to be scanned without light, got %v", scans)
+	}
+	if scans := drone.PredictScans(state, V(5000, 4000), true); len(scans) != 2 {
+		t.Errorf("Expected both creatures to be scanned with light, got %v", scans)
+	}
+}
+
+func TestApproachPoint_StopsAtScanRadiusEdge(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 500, 0, 30)
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateCreature(4, 5000, 4500, 0, 0)
+	drone := state.GetDrone(0)
+
+	point := drone.ApproachPoint(state.GetCreature(4))
+
+	if point != V(5000, 4500-ScanRadius+FishSpeed) {
+		t.Errorf("Expected to stop at the scan radius edge minus a fish move, got %v", point)
+	}
+}
+
+func TestChooseEndPoint_SweepsNearbyFish(t *testing.T) {
+	state := NewGameState()
+	state.UpdateMyDrone(0, 5000, 3000, 0, 30)
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, MediumFish))
+	state.UpdateCreature(4, 5000, 9000, 0, 0)
+	state.UpdateCreature(5, 5900, 3400, 0, 0)
+	drone := state.GetDrone(0)
+	drone.Target = state.GetCreature(4)
+
+	end := drone.ChooseEndPoint(state)
+
+	if end.Dist(V(5900, 3400)) > ScanRadius {
+		t.Errorf("Expected end point %v to sweep creature 5", end)
+	}
+}
@@ -2,39 +2,78 @@ package main
 
 // CalculatePotentialPoints calculates the potential score current turn if all my drones ascend
 func (state *GameState) CalculatePotentialPoints() int {
-	potentialPoints := state.MyScore // Start with current score
+	return state.projectPoints(state.MyScore, state.MyDrones, state.MyScans, state.FoeScans)
+}
+
+// CalculateFoePotentialPoints calculates the potential foe score current turn if all foe drones ascend
+func (state *GameState) CalculateFoePotentialPoints() int {
+	return state.projectPoints(state.FoeScore, state.FoeDrones, state.FoeScans, state.MyScans)
+}
+
+// projectPoints adds the points of the unsaved scans of the drones to the score, drones in emergency
+// lost their scans to a monster and bring nothing
+func (state *GameState) projectPoints(score int, drones []*Drone, saved, foeSaved []*Creature) int {
+	potentialPoints := score // Start with current score
 
-	// Temporary map to hold counts of each type and color of fish scanned
-	typeCounts := make(map[CreatureType]int)
-	colorCounts := make(map[int]int)
-	firstScanBonus := make(map[int]bool) // Track if you're the first to scan a particular fish
+	counted := make(map[int]bool) // Both drones may hold the same scan
 
-	// Check scans in both of your drones
-	for _, drone := range state.MyDrones {
+	// Check scans in both drones
+	for _, drone := range drones {
+		if drone.IsEmergency() {
+			continue
+		}
 		for _, scan := range drone.Scans {
-			if !scan.IsDelivered(state) {
-				potentialPoints += getScanPoints(scan.Type, firstScanBonus[scan.Id])
-				typeCounts[scan.Type]++
-				colorCounts[scan.Color]++
-				firstScanBonus[scan.Id] = true // Assume you're the first for this calculation
+			if counted[scan.Id] || containsCreature(saved, scan.Id) {
+				continue
 			}
+			// Saving first doubles the points unless the other side already saved it
+			potentialPoints += getScanPoints(scan.Type, !containsCreature(foeSaved, scan.Id))
+			counted[scan.Id] = true
 		}
 	}
 
-	// Add bonus points for scanning all fish of one type or color
-	for _, count := range typeCounts {
-		if count == len(state.Creatures) {
-			potentialPoints += 4 // Bonus for all fish of one type
+	// Add bonus points for sets of one color or type the scans complete, doubled if the other side has not
+	for _, set := range state.CreatureSets() {
+		completes := false
+		for _, member := range set.Members {
+			if counted[member.Id] {
+				completes = true
+			} else if !containsCreature(saved, member.Id) {
+				completes = false
+				break
+			}
+		}
+		if !completes {
+			continue
+		}
+		points := set.Points
+		if !containsAllCreatures(foeSaved, set.Members) {
+			points *= 2
 		}
+		potentialPoints += points
 	}
 
-	for _, count := range colorCounts {
-		if count == len(state.Creatures) {
-			potentialPoints += 3 // Bonus for all fish of one color
+	return potentialPoints
+}
+
+// containsAllCreatures returns true if all creatures of the set are in the slice
+func containsAllCreatures(creatures []*Creature, set []*Creature) bool {
+	for _, creature := range set {
+		if !containsCreature(creatures, creature.Id) {
+			return false
 		}
 	}
+	return true
+}
 
-	return potentialPoints
+// containsCreature returns true if the creature with the given id is in the slice
+func containsCreature(creatures []*Creature, id int) bool {
+	for _, creature := range creatures {
+		if creature.Id == id {
+			return true
+		}
+	}
+	return false
 }
 
 func getScanPoints(fishType CreatureType, first bool) int {
@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestCalculatePotentialPoints_EmergencyDroneScansLost(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.AddCreature(NewCreature(6, 1, DeepFish))
+	state.AddCreature(NewCreature(7, 0, ShallowFish))
+	state.UpdateMyDrone(0, 5000, 8000, 0, 30)
+	state.UpdateMyDrone(2, 6000, 8000, 0, 30)
+	state.GetDrone(0).AddScan(state.GetCreature(4))
+	state.GetDrone(2).AddScan(state.GetCreature(5))
+
+	if points := state.CalculatePotentialPoints(); points != 8 {
+		t.Errorf("Expected 8 points before the hit, got %d", points)
+	}
+
+	state.UpdateMyDrone(0, 5000, 8000, 1, 30)
+
+	if points := state.CalculatePotentialPoints(); points != 2 {
+		t.Errorf("Expected 2 points after drone 0 was hit, got %d", points)
+	}
+}
+
+func TestCalculateFoePotentialPoints_FirstSaveBonus(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, MediumFish))
+	state.AddCreature(NewCreature(6, 1, DeepFish))
+	state.AddCreature(NewCreature(7, 0, MediumFish))
+	state.AddMyScan(4)
+	state.UpdateFoeDrone(1, 5000, 8000, 0, 30)
+	state.GetDrone(1).AddScan(state.GetCreature(4))
+	state.GetDrone(1).AddScan(state.GetCreature(5))
+
+	if points := state.CalculateFoePotentialPoints(); points != 7 {
+		t.Errorf("Expected 3 points for the fish I saved first and 4 for the other, got %d", points)
+	}
+}
+
+func TestCalculatePotentialPoints_SetCompletion(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 0, ShallowFish))
+	state.AddCreature(NewCreature(6, 1, DeepFish))
+	state.AddMyScan(5)
+	state.AddFoeScan(4)
+	state.AddFoeScan(5)
+	state.UpdateMyDrone(0, 5000, 8000, 0, 30)
+	state.GetDrone(0).AddScan(state.GetCreature(4))
+
+	// 3 for the fish, 3 for color 0 which the foe completed first
+	if points := state.CalculatePotentialPoints(); points != 6 {
+		t.Errorf("Expected 6 points, got %d", points)
+	}
+
+	state.GetDrone(0).AddScan(state.GetCreature(6))
+
+	// 6 more for fish 6, 6 for color 1 and 8 for the deep type, both completed first
+	if points := state.CalculatePotentialPoints(); points != 26 {
+		t.Errorf("Expected 26 points, got %d", points)
+	}
+}
+
+func TestUpdateMyDrone_EmergencyReleasesTarget(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.UpdateMyDrone(0, 5000, 8000, 0, 30)
+	drone := state.GetDrone(0)
+	drone.Target = state.GetCreature(4)
+
+	state.UpdateMyDrone(0, 5000, 8000, 1, 30)
+
+	if drone.Target != nil || state.GetCreature(4).IsTargeted(state, nil) {
+		t.Errorf("Expected target to be released")
+	}
+	if turns := drone.TurnsUntilRepaired(); turns != 25 {
+		t.Errorf("Expected 25 turns to float up, got %d", turns)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import "fmt"
+
+// CreatureSet is a group of fish bringing a combo bonus once all of them are saved: one color or one type.
+type CreatureSet struct {
+	Name    string
+	Points  int
+	Members []*Creature
+}
+
+// SetProgress is how far both players are in completing a set.
+type SetProgress struct {
+	Set          CreatureSet
+	MyMissing    []*Creature // fish I have neither saved nor carry
+	FoeMissing   []*Creature // fish the foe has neither saved nor carries
+	MyCompleted  bool
+	FoeCompleted bool
+	MyPossible   bool // no fish I miss has left the map
+	FoePossible  bool
+}
+
+// CreatureSets returns all color and type sets of the fish received at init.
+func (state *GameState) CreatureSets() []CreatureSet {
+	if state.index.sets != nil {
+		return state.index.sets
+	}
+	var sets []CreatureSet
+	colorSets := map[int]int{}
+	typeSets := map[CreatureType]int{}
+	for _, creature := range state.Creatures {
+		if creature.Type == Monster {
+			continue
+		}
+		if index, ok := colorSets[creature.Color]; ok {
+			sets[index].Members = append(sets[index].Members, creature)
+		} else {
+			colorSets[creature.Color] = len(sets)
+			sets = append(sets, CreatureSet{Name: fmt.Sprintf("color %d", creature.Color), Points: ColorComboPoints, Members: []*Creature{creature}})
+		}
+		if index, ok := typeSets[creature.Type]; ok {
+			sets[index].Members = append(sets[index].Members, creature)
+		} else {
+			typeSets[creature.Type] = len(sets)
+			sets = append(sets, CreatureSet{Name: fmt.Sprintf("type %d", creature.Type), Points: TypeComboPoints, Members: []*Creature{creature}})
+		}
+	}
+	state.index.sets = sets
+	return sets
+}
+
+// Progress returns the completion state of the set for both players.
+func (state *GameState) Progress(set CreatureSet) SetProgress {
+	progress := SetProgress{Set: set, MyCompleted: true, FoeCompleted: true, MyPossible: true, FoePossible: true}
+	for _, creature := range set.Members {
+		if !creature.IsDelivered(state) {
+			progress.MyCompleted = false
+			if !creature.IsScanned(state) {
+				progress.MyMissing = append(progress.MyMissing, creature)
+				progress.MyPossible = progress.MyPossible && !creature.Dead
+			}
+		}
+		if !creature.IsDeliveredByFoe(state) {
+			progress.FoeCompleted = false
+			if !creature.IsScannedByFoe(state) {
+				progress.FoeMissing = append(progress.FoeMissing, creature)
+				progress.FoePossible = progress.FoePossible && !creature.Dead
+			}
+		}
+	}
+	return progress
+}
+
+// FoeCanCompleteFirst returns true if the foe may complete the set before me, taking away the doubling.
+func (progress SetProgress) FoeCanCompleteFirst() bool {
+	if progress.FoeCompleted {
+		return true
+	}
+	return progress.FoePossible && !progress.MyCompleted && len(progress.FoeMissing) <= len(progress.MyMissing)
+}
+
+// MarginalValue returns the points scanning the creature is expected to bring me: its own points, doubled
+// if the foe has not saved it yet, and a share of the bonus of each set it helps to complete.
+func (state *GameState) MarginalValue(creature *Creature) float64 {
+	if creature.Type == Monster || creature.IsDelivered(state) || creature.IsScanned(state) {
+		return 0
+	}
+	value := float64(getScanPoints(creature.Type, !creature.IsDeliveredByFoe(state)))
+	for _, set := range state.CreatureSets() {
+		if !containsCreature(set.Members, creature.Id) {
+			continue
+		}
+		progress := state.Progress(set)
+		if !progress.MyPossible {
+			continue
+		}
+		bonus := set.Points
+		if !progress.FoeCanCompleteFirst() {
+			bonus *= 2
+		}
+		// Every missing fish is needed for the bonus, split it between them
+		value += float64(bonus) / float64(len(progress.MyMissing))
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestCreatureSets(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.AddCreature(NewCreature(6, 0, DeepFish))
+	state.AddCreature(NewCreature(16, -1, Monster))
+
+	sets := state.CreatureSets()
+
+	// color 0, type 0, color 1, type 2
+	if len(sets) != 4 {
+		t.Fatalf("Expected 4 sets, got %d", len(sets))
+	}
+	if sets[0].Points != ColorComboPoints || len(sets[0].Members) != 2 {
+		t.Errorf("Expected color 0 set with 2 fish, got %v", sets[0])
+	}
+	if sets[1].Points != TypeComboPoints || len(sets[1].Members) != 2 {
+		t.Errorf("Expected shallow type set with 2 fish, got %v", sets[1])
+	}
+}
+
+func TestMarginalValue_SetShares(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.AddCreature(NewCreature(6, 0, DeepFish))
+	state.AddMyScan(5)
+
+	// 2 for the fish, half of the color 0 bonus the foe is as close to and the doubled shallow type bonus
+	if value := state.MarginalValue(state.GetCreature(4)); value != 2+1.5+8 {
+		t.Errorf("Expected 11.5, got %.1f", value)
+	}
+}
+
+func TestMarginalValue_FoeAheadOnSet(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.AddFoeScan(5)
+
+	// Foe needs as few or fewer fish as me for both sets, so no bonus is doubled
+	if value := state.MarginalValue(state.GetCreature(4)); value != 2+3+2 {
+		t.Errorf("Expected 7, got %.1f", value)
+	}
+}
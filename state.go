@@ -1,5 +1,10 @@
 package main
 
+import (
+	"io"
+	"os"
+)
+
 type GameState struct {
 	MyScore      int
 	FoeScore     int
@@ -11,51 +16,57 @@ type GameState struct {
 	MyScans      []*Creature
 	FoeScans     []*Creature
 	Turn         int
+	Out          io.Writer
+	RadarHistory RadarHistory
+	twins        map[int]*Creature
+	index        stateIndex
+	danger       *DangerField
 }
 
-// NewGameState returns a new GameState.
+// NewGameState returns a new GameState writing drone commands to stdout.
 func NewGameState() *GameState {
-	return &GameState{}
+	return &GameState{Out: os.Stdout}
 }
 
 // UpdateMyDrone updates the drone with the given ID in the GameState's MyDrones or adds new if not present.
 func (state *GameState) UpdateMyDrone(id, x, y, emergency, battery int) {
-	for _, drone := range state.MyDrones {
-		if drone.Id == id {
-			drone.X = x
-			drone.Y = y
-			drone.Emergency = emergency
-			drone.Battery = battery
-			return
-		}
+	if drone := state.index.drone(id); drone != nil {
+		drone.Pos = V(x, y)
+		drone.Battery = battery
+		drone.UpdateEmergency(emergency)
+		return
 	}
-	state.MyDrones = append(state.MyDrones, &Drone{
-		Id:        id,
-		X:         x,
-		Y:         y,
-		Emergency: emergency,
-		Battery:   battery,
-	})
+	drone := &Drone{
+		Id:            id,
+		Pos:           V(x, y),
+		Emergency:     emergency,
+		Battery:       battery,
+		LastLightTurn: NotInitialized,
+	}
+	state.MyDrones = append(state.MyDrones, drone)
+	state.index.addDrone(drone)
 }
 
 // UpdateFoeDrone updates the drone with the given ID in the GameState's FoeDrones or adds new if not present.
 func (state *GameState) UpdateFoeDrone(id, x, y, emergency, battery int) {
-	for _, drone := range state.FoeDrones {
-		if drone.Id == id {
-			drone.X = x
-			drone.Y = y
-			drone.Emergency = emergency
-			drone.Battery = battery
-			return
-		}
+	if drone := state.index.drone(id); drone != nil {
+		drone.Pos = V(x, y)
+		drone.PrevBattery = drone.Battery
+		drone.Battery = battery
+		drone.UpdateEmergency(emergency)
+		drone.RecordPosition()
+		return
 	}
-	state.FoeDrones = append(state.FoeDrones, &Drone{
-		Id:        id,
-		X:         x,
-		Y:         y,
-		Emergency: emergency,
-		Battery:   battery,
-	})
+	drone := &Drone{
+		Id:          id,
+		Pos:         V(x, y),
+		Emergency:   emergency,
+		Battery:     battery,
+		PrevBattery: battery,
+	}
+	drone.RecordPosition()
+	state.FoeDrones = append(state.FoeDrones, drone)
+	state.index.addDrone(drone)
 }
 
 // AddCreature adds a creature to the GameState's Creatures slice.
@@ -64,88 +75,74 @@ func (state *GameState) AddCreature(creature *Creature) {
 		state.Creatures = make([]*Creature, 0)
 	}
 	state.Creatures = append(state.Creatures, creature)
+	state.index.addCreature(creature)
+	state.index.sets = nil
+	state.twins = nil
 }
 
 // UpdateCreature updates the creature with the given ID in the GameState's
-// Creatures slice.
+// Creatures slice. The reported velocity is kept as is rather than smoothed over
+// sightings, the engine moves the creature by exactly that velocity next turn.
 func (state *GameState) UpdateCreature(id, x, y, vx, vy int) {
-	for _, creature := range state.Creatures {
-		if creature.Id == id {
-			creature.X = x
-			creature.Y = y
-			creature.Vx = vx
-			creature.Vy = vy
-			creature.LastVisibleTurn = state.Turn
-			return
-		}
+	creature := state.index.creature(id)
+	if creature == nil {
+		return
 	}
+	creature.Pos = V(x, y)
+	creature.Speed = V(vx, vy)
+	creature.LastVisibleTurn = state.Turn + 1
+	creature.Bounds = Box{x, y, x, y}
+	creature.LastSeen = creature.Pos
 }
 
 // UpdateRadarBlip updates the radar blip with the given ID in the GameState's
-// MyDrones or FoeDrones slice.
+// MyDrones or FoeDrones slice and records it in the radar history.
 func (state *GameState) UpdateRadarBlip(droneId, creatureId int, radar string) {
 	drone := state.GetDrone(droneId)
 	if drone == nil {
 		return
 	}
 	drone.AddRadarBlip(creatureId, RadarBlip(radar))
+	// Blips are read before the turn counter moves on to the turn they belong to
+	state.RadarHistory.Add(RadarRecord{
+		Turn:       state.Turn + 1,
+		DroneId:    droneId,
+		DronePos:   drone.Pos,
+		CreatureId: creatureId,
+		Blip:       RadarBlip(radar),
+	})
 }
 
 // AddMyScan adds a creature to the GameState's MyScans slice if not present.
 func (state *GameState) AddMyScan(creatureId int) {
-	for _, creature := range state.MyScans {
-		if creature.Id == creatureId {
-			return
-		}
-	}
-	for _, creature := range state.Creatures {
-		if creature.Id == creatureId {
-			state.MyScans = append(state.MyScans, creature)
-			return
-		}
+	creature := state.index.creature(creatureId)
+	if creature == nil || state.index.mySaved.Has(creatureId) {
+		return
 	}
+	state.MyScans = append(state.MyScans, creature)
+	state.index.mySaved = state.index.mySaved.Add(creatureId)
 }
 
 // AddFoeScan adds a creature to the GameState's FoeScans slice if not present.
 func (state *GameState) AddFoeScan(creatureId int) {
-	for _, creature := range state.FoeScans {
-		if creature.Id == creatureId {
-			return
-		}
-	}
-	for _, creature := range state.Creatures {
-		if creature.Id == creatureId {
-			state.FoeScans = append(state.FoeScans, creature)
-			return
-		}
+	creature := state.index.creature(creatureId)
+	if creature == nil || state.index.foeSaved.Has(creatureId) {
+		return
 	}
+	state.FoeScans = append(state.FoeScans, creature)
+	state.index.foeSaved = state.index.foeSaved.Add(creatureId)
 }
 
 // GetDrone returns the drone with the given ID from the GameState's MyDrones or
 // FoeDrones slice.
 func (state *GameState) GetDrone(id int) *Drone {
-	for _, drone := range state.MyDrones {
-		if drone.Id == id {
-			return drone
-		}
-	}
-	for _, drone := range state.FoeDrones {
-		if drone.Id == id {
-			return drone
-		}
-	}
-	return nil
+	return state.index.drone(id)
 }
 
 // GetCreature returns the creature with the given ID from the GameState's
 // Creatures slice.
 func (state *GameState) GetCreature(id int) *Creature {
-	for _, creature := range state.Creatures {
-		if creature.Id == id {
-			return creature
-		}
-	}
-	return nil
+	return state.index.creature(id)
 }
 
 // GetMonsters get only monster type creatures
@@ -159,37 +156,52 @@ func (state *GameState) GetMonsters() []*Creature {
 	return monsters
 }
 
-// Print all state information each in new line
+// Print logs all state information each in new line, at debug level as it floods the console
 func (state *GameState) Print() {
-	Log("Turn:", state.Turn)
-	Log("My score:", state.MyScore)
-	Log("Foe score:", state.FoeScore)
+	if !logger.Enabled(LevelDebug, CategoryParse) && !logger.Enabled(LevelDebug, CategoryAvoid) {
+		return
+	}
+	logger.Debug(CategoryParse, "Turn:", state.Turn)
+	logger.Debug(CategoryParse, "My score:", state.MyScore)
+	logger.Debug(CategoryParse, "Foe score:", state.FoeScore)
 
 	// print creatures skipping monsters
-	Log("Creatures:")
+	logger.Debug(CategoryParse, "Creatures:")
 	for _, creature := range state.Creatures {
 
 		if creature.Type == Monster {
 			continue
 		}
-		Log(creature.String())
+		logger.Debug(CategoryParse, creature.String())
 	}
 
-	Log("My drones:")
+	logger.Debug(CategoryParse, "My drones:")
 
 	for _, drone := range state.MyDrones {
-		Log(drone.String())
+		logger.Debug(CategoryParse, drone.String())
+	}
+
+	logger.Debug(CategoryParse, "Foe drones:")
+	for _, drone := range state.FoeDrones {
+		logger.Debug(CategoryParse, drone.String())
+		if record, ok := drone.LastBehavior(); ok {
+			logger.Debug(CategoryParse, record.String())
+		}
 	}
 	// prin all monsters
-	Log("Monsters:")
+	logger.Debug(CategoryParse, "Monsters:")
 	for _, monster := range state.GetMonsters() {
-		Log(monster.String())
+		logger.Debug(CategoryParse, monster.String())
+	}
+
+	if logger.Enabled(LevelDebug, CategoryAvoid) {
+		logger.Debug(CategoryAvoid, state.Danger().Render(1))
 	}
 
 	// print distances from drones to monsters
 	for _, drone := range state.MyDrones {
 		for _, creature := range state.GetMonsters() {
-			Log("Distance from drone", drone.Id, "to monster", creature.Id, "is", distance(drone.X, drone.Y, creature.X, creature.Y))
+			logger.Debug(CategoryAvoid, "Distance from drone", drone.Id, "to monster", creature.Id, "is", int(drone.Pos.Dist(creature.Pos)))
 		}
 	}
 }
@@ -221,9 +233,15 @@ func (state *GameState) PrepareForNextTurn() {
 	// Clear scans
 	state.MyScans = []*Creature{}
 	state.FoeScans = []*Creature{}
+	state.index.mySaved, state.index.foeSaved = 0, 0
 	// Clear radar blips
 	for _, drone := range state.MyDrones {
 		drone.ClearRadarBlips()
 		drone.ClearScans()
+		drone.Next = nil
+	}
+	// Foe drone scans are sent again every turn as well
+	for _, drone := range state.FoeDrones {
+		drone.ClearScans()
 	}
 }
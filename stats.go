@@ -0,0 +1,333 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// runStats reads recorded games and prints where a bot gets and loses its points.
+func runStats(args []string) int {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: stats [flags] <replay.json|directory> ...")
+		fmt.Fprintln(flags.Output(), "Strategies:", strings.Join(StrategyNames(), ", "))
+		flags.PrintDefaults()
+	}
+	botName := flags.String("bot", "default", "name of the bot in the replays to report on")
+	strategyName := flags.String("strategy", "", "strategy rebuilding the beliefs of the bot for the estimator error, defaults to the bot name if it is a strategy")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *strategyName == "" {
+		if _, err := NewStrategy(*botName); err == nil {
+			*strategyName = *botName
+		}
+	}
+
+	paths, err := replayPaths(flags.Args())
+	if err != nil {
+		logger.Warn(CategoryTool, err)
+		return 1
+	}
+	previous := logWriter
+	logWriter = io.Discard
+	defer func() { logWriter = previous }()
+
+	report := NewStatsReport(*botName)
+	for _, path := range paths {
+		replay, err := LoadReplay(path)
+		if err != nil {
+			logger.Warn(CategoryTool, err)
+			return 1
+		}
+		if err := report.Add(replay, *strategyName); err != nil {
+			logger.Warn(CategoryTool, path+":", err)
+			return 1
+		}
+	}
+	report.Print(os.Stdout)
+	return 0
+}
+
+// replayPaths expands directories into the replays they hold.
+func replayPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// errorSum accumulates estimation errors.
+type errorSum struct {
+	Total float64
+	Count int
+}
+
+// MatchStats is what happened to one side of one recorded game.
+type MatchStats struct {
+	Player          int
+	Outcome         float64
+	Score           ScoreBreakdown
+	FirstSaveTurn   int // 0 without any save
+	Dives           int // surfacings with scans and hits, not the saves at the end of the game
+	DiveScans       int // scans saved or lost on those dives
+	Emergencies     int
+	HitsByMonster   map[int]int
+	ScansLost       int
+	FishLost        int // fish scared off the map by the side
+	FishLostUnsaved int // of those, fish the side never saved
+	Lights          int
+	LightScans      int // scans only the light reached
+	EstimateErrors  map[CreatureType]*errorSum
+}
+
+// AnalyzeReplay computes the statistics of a player from the events of the replay and, with a strategy,
+// the error of its estimates against the truth by rebuilding its beliefs.
+func AnalyzeReplay(replay *Replay, player int, strategy Strategy) (*MatchStats, error) {
+	stats := &MatchStats{
+		Player:         player,
+		Outcome:        replay.Result.Outcome(),
+		Score:          replay.Breakdown[player],
+		HitsByMonster:  map[int]int{},
+		EstimateErrors: map[CreatureType]*errorSum{},
+	}
+	if player == 1 {
+		stats.Outcome = 1 - stats.Outcome
+	}
+
+	saved := map[int]bool{}
+	dives := map[[2]int]bool{}
+	for _, event := range replay.Events {
+		if event.Kind == EventSave && event.Player == player {
+			saved[event.Creature] = true
+		}
+	}
+	for _, event := range replay.Events {
+		if event.Player != player {
+			continue
+		}
+		switch event.Kind {
+		case EventSave:
+			if stats.FirstSaveTurn == 0 {
+				stats.FirstSaveTurn = event.Turn
+			}
+			if !event.Final {
+				dives[[2]int{event.Turn, event.Drone}] = true
+				stats.DiveScans++
+			}
+		case EventHit:
+			stats.Emergencies++
+			stats.HitsByMonster[event.Creature]++
+			stats.ScansLost += event.Scans
+			stats.Dives++
+			stats.DiveScans += event.Scans
+		case EventLost:
+			stats.FishLost++
+			if !saved[event.Creature] {
+				stats.FishLostUnsaved++
+			}
+		case EventLight:
+			stats.Lights++
+		case EventScan:
+			if event.Light && event.Distance > ScanRadius {
+				stats.LightScans++
+			}
+		}
+	}
+	stats.Dives += len(dives)
+
+	if strategy == nil {
+		return stats, nil
+	}
+	bot, err := NewReplayBot(replay, player, strategy)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if err := bot.Step(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		state, turn := bot.State, bot.Turn()
+		for _, creature := range state.Creatures {
+			if creature.Dead || creature.LastVisibleTurn == state.Turn {
+				continue
+			}
+			truth := turn.creature(creature.Id)
+			if truth == nil || truth.Lost {
+				continue
+			}
+			sum := stats.EstimateErrors[creature.Type]
+			if sum == nil {
+				sum = &errorSum{}
+				stats.EstimateErrors[creature.Type] = sum
+			}
+			sum.Total += creature.Pos.Dist(V(truth.X, truth.Y))
+			sum.Count++
+		}
+	}
+	return stats, nil
+}
+
+// StatsReport aggregates the statistics of one bot over many recorded games.
+type StatsReport struct {
+	Bot     string
+	Matches []*MatchStats
+	Skipped int // replays the bot did not play in
+	Rebuilt bool
+}
+
+// NewStatsReport returns an empty report of the named bot.
+func NewStatsReport(bot string) *StatsReport {
+	return &StatsReport{Bot: bot}
+}
+
+// Add analyzes every side of the replay played by the bot, rebuilding its beliefs with the named strategy
+// if there is one.
+func (report *StatsReport) Add(replay *Replay, strategyName string) error {
+	found := false
+	for player, name := range replay.Result.Names {
+		if name != report.Bot {
+			continue
+		}
+		found = true
+		var strategy Strategy
+		if strategyName != "" {
+			var err error
+			if strategy, err = NewStrategy(strategyName); err != nil {
+				return err
+			}
+			report.Rebuilt = true
+		}
+		stats, err := AnalyzeReplay(replay, player, strategy)
+		if err != nil {
+			return err
+		}
+		report.Matches = append(report.Matches, stats)
+	}
+	if !found {
+		report.Skipped++
+	}
+	return nil
+}
+
+// Print writes the aggregated statistics.
+func (report *StatsReport) Print(out io.Writer) {
+	games := len(report.Matches)
+	fmt.Fprintf(out, "Bot %s in %d games", report.Bot, games)
+	if report.Skipped > 0 {
+		fmt.Fprintf(out, ", %d replays without it", report.Skipped)
+	}
+	fmt.Fprintln(out)
+	if games == 0 {
+		return
+	}
+
+	var wins, draws int
+	var score ScoreBreakdown
+	var firstSaves, firstSaveTurns, dives, diveScans, emergencies, scansLost, fishLost, fishLostUnsaved, lights, lightScans int
+	hits := map[int]int{}
+	estimates := map[CreatureType]*errorSum{}
+	for _, stats := range report.Matches {
+		switch stats.Outcome {
+		case 1:
+			wins++
+		case 0.5:
+			draws++
+		}
+		score.Base += stats.Score.Base
+		score.FirstSave += stats.Score.FirstSave
+		score.Combo += stats.Score.Combo
+		score.FirstCombo += stats.Score.FirstCombo
+		if stats.FirstSaveTurn > 0 {
+			firstSaves++
+			firstSaveTurns += stats.FirstSaveTurn
+		}
+		dives += stats.Dives
+		diveScans += stats.DiveScans
+		emergencies += stats.Emergencies
+		scansLost += stats.ScansLost
+		for id, count := range stats.HitsByMonster {
+			hits[id] += count
+		}
+		fishLost += stats.FishLost
+		fishLostUnsaved += stats.FishLostUnsaved
+		lights += stats.Lights
+		lightScans += stats.LightScans
+		for creatureType, sum := range stats.EstimateErrors {
+			if estimates[creatureType] == nil {
+				estimates[creatureType] = &errorSum{}
+			}
+			estimates[creatureType].Total += sum.Total
+			estimates[creatureType].Count += sum.Count
+		}
+	}
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Results\t%d wins, %d draws, %d losses\n", wins, draws, games-wins-draws)
+	fmt.Fprintf(table, "Avg score\t%.1f = base %.1f + first save %.1f + combo %.1f + first combo %.1f\n",
+		average(score.Total(), games), average(score.Base, games), average(score.FirstSave, games),
+		average(score.Combo, games), average(score.FirstCombo, games))
+	fmt.Fprintf(table, "First save\tturn %.1f on average, none in %d games\n", average(firstSaveTurns, firstSaves), games-firstSaves)
+	fmt.Fprintf(table, "Scans per dive\t%.2f over %d dives\n", average(diveScans, dives), dives)
+	fmt.Fprintf(table, "Emergencies\t%.2f per game, %d scans lost, by monster %s\n", average(emergencies, games), scansLost, countsText(hits))
+	fmt.Fprintf(table, "Fish lost\t%.2f per game scared off the map, %.2f never saved\n", average(fishLost, games), average(fishLostUnsaved, games))
+	fmt.Fprintf(table, "Light\t%.1f uses per game, %.2f scans only the light reached per use\n", average(lights, games), average(lightScans, lights))
+	_ = table.Flush()
+
+	if !report.Rebuilt {
+		fmt.Fprintln(out, "Estimator error not measured, no strategy to rebuild the beliefs of the bot")
+		return
+	}
+	fmt.Fprintln(out)
+	table = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Estimates\tAvg error\tSamples")
+	for _, creatureType := range []CreatureType{ShallowFish, MediumFish, DeepFish, Monster} {
+		if sum := estimates[creatureType]; sum != nil {
+			fmt.Fprintf(table, "%s\t%.0f\t%d\n", creatureTypeName(creatureType), sum.Total/float64(sum.Count), sum.Count)
+		}
+	}
+	_ = table.Flush()
+}
+
+// countsText returns counts by id sorted by id, e.g. "16:2 19:1".
+func countsText(counts map[int]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d:%d", id, counts[id])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeReplay_CountsEvents(t *testing.T) {
+	replay := &Replay{
+		Result:    MatchResult{Names: [2]string{"idle", "default"}, Scores: [2]int{0, 12}},
+		Breakdown: [2]ScoreBreakdown{{}, {Base: 6, FirstSave: 6}},
+		Events: []MatchEvent{
+			{Turn: 3, Kind: EventLight, Player: 1, Drone: 1, Creature: -1},
+			{Turn: 3, Kind: EventScan, Player: 1, Drone: 1, Creature: 4, Distance: 1500, Light: true},
+			{Turn: 4, Kind: EventScan, Player: 1, Drone: 3, Creature: 5, Distance: 700},
+			{Turn: 6, Kind: EventHit, Player: 1, Drone: 3, Creature: 16, Scans: 1},
+			{Turn: 9, Kind: EventLost, Player: 1, Drone: 1, Creature: 6},
+			{Turn: 12, Kind: EventSave, Player: 1, Drone: 1, Creature: 4, First: true},
+			{Turn: 12, Kind: EventSave, Player: 1, Drone: 1, Creature: 7, First: true},
+			{Turn: 12, Kind: EventHit, Player: 0, Drone: 0, Creature: 17},
+			{Turn: 201, Kind: EventSave, Player: 1, Drone: 3, Creature: 8, Final: true},
+		},
+	}
+
+	stats, err := AnalyzeReplay(replay, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Outcome != 1 || stats.Score.Total() != 12 || stats.FirstSaveTurn != 12 {
+		t.Errorf("Expected a win of 12 points with a first save on turn 12, got %+v", stats)
+	}
+	if stats.Dives != 2 || stats.DiveScans != 3 {
+		t.Errorf("Expected 3 scans over a surfacing and a hit, got %d over %d", stats.DiveScans, stats.Dives)
+	}
+	if stats.Emergencies != 1 || stats.HitsByMonster[16] != 1 || stats.ScansLost != 1 {
+		t.Errorf("Expected one hit by monster 16 losing a scan, got %+v", stats)
+	}
+	if stats.FishLost != 1 || stats.FishLostUnsaved != 1 || stats.Lights != 1 || stats.LightScans != 1 {
+		t.Errorf("Expected one unsaved fish lost and one light scan, got %+v", stats)
+	}
+}
+
+func TestSaveDrone_FirstBeforeTheFoe(t *testing.T) {
+	referee := NewReferee(1)
+	me, foe := referee.Players[0], referee.Players[1]
+	foe.SaveTurn[4], foe.SaveTurn[5] = 10, 12
+	drone := me.Drones[0]
+	drone.Scans = []int{4, 5, 6}
+
+	referee.saveDrone(0, drone, 12, false)
+
+	first := map[int]bool{}
+	for _, event := range referee.Events {
+		first[event.Creature] = event.First
+	}
+	if first[4] || !first[5] || !first[6] {
+		t.Errorf("Expected only the fish the foe saved earlier not to be first, got %v", first)
+	}
+}
+
+func TestStatsReport_RecordedMatch(t *testing.T) {
+	replay := recordedMatch(t)
+	for p := range replay.Breakdown {
+		if total := replay.Breakdown[p].Total(); total != replay.Result.Scores[p] {
+			t.Errorf("Expected the breakdown of player %d to add up to %d, got %d", p, replay.Result.Scores[p], total)
+		}
+	}
+	report := NewStatsReport("default")
+
+	if err := report.Add(replay, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Add(&Replay{Result: MatchResult{Names: [2]string{"a", "b"}}}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Matches) != 1 || report.Skipped != 1 {
+		t.Fatalf("Expected one match and one skipped replay, got %d and %d", len(report.Matches), report.Skipped)
+	}
+	if sum := report.Matches[0].EstimateErrors[DeepFish]; sum == nil || sum.Count == 0 {
+		t.Errorf("Expected deep fish estimates to be measured")
+	}
+	var out strings.Builder
+	report.Print(&out)
+	for _, expected := range []string{"Bot default in 1 games", "Scans per dive", "deep"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const stepperHelp = `Commands:
+  n [count]                  next turn, or count turns forward
+  p [count]                  previous turn, or count turns back
+  g <turn>                   go to the turn
+  c [id]                     show only the creature, all creatures without id
+  r [strategy] [log config]  re-run the decision of this turn, logging with the config (e.g. debug,target)
+  s                          show the turn again
+  h                          show this help
+  q                          quit
+`
+
+// runStep loads a recorded game and steps through it in the terminal.
+func runStep(args []string) int {
+	flags := flag.NewFlagSet("step", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: step [flags] <replay.json>")
+		fmt.Fprintln(flags.Output(), "Strategies:", strings.Join(StrategyNames(), ", "))
+		flags.PrintDefaults()
+	}
+	player := flags.Int("player", 0, "player whose beliefs are shown")
+	strategyName := flags.String("strategy", "default", "strategy rebuilding the beliefs of the player")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *player < 0 || *player > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	replay, err := LoadReplay(flags.Arg(0))
+	if err != nil {
+		logger.Warn(CategoryTool, err)
+		return 1
+	}
+	if _, err := NewStrategy(*strategyName); err != nil {
+		logger.Warn(CategoryTool, err)
+		return 2
+	}
+	stepper := &Stepper{Replay: replay, Player: *player, Strategy: *strategyName, Filter: -1}
+	if err := stepper.Run(os.Stdin, os.Stdout); err != nil {
+		logger.Warn(CategoryTool, err)
+		return 1
+	}
+	return 0
+}
+
+// Stepper shows a recorded game turn by turn from the point of view of one player. Going to a turn
+// rebuilds the beliefs of the player by replaying its input from the start, the bot being deterministic.
+type Stepper struct {
+	Replay   *Replay
+	Player   int
+	Strategy string
+	Filter   int // creature to show alone, -1 for all
+	bot      *ReplayBot
+}
+
+// Run reads commands from in until it is exhausted or the user quits, writing the turns to out.
+func (stepper *Stepper) Run(in io.Reader, out io.Writer) error {
+	// The bot logs go to the terminal only when asked for by a re-run
+	previous := logWriter
+	logWriter = io.Discard
+	defer func() { logWriter = previous }()
+
+	if err := stepper.Goto(1); err != nil {
+		return err
+	}
+	stepper.Print(out)
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "turn %d> ", stepper.Turn())
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		quit, err := stepper.Execute(strings.Fields(scanner.Text()), out)
+		if err != nil {
+			fmt.Fprintln(out, err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// Execute runs one command, returning true when the user quits.
+func (stepper *Stepper) Execute(fields []string, out io.Writer) (bool, error) {
+	if len(fields) == 0 {
+		return false, nil
+	}
+	args := fields[1:]
+	switch fields[0] {
+	case "n", "next", "p", "prev":
+		count, err := optionalInt(args, 1)
+		if err != nil {
+			return false, err
+		}
+		if fields[0][0] == 'p' {
+			count = -count
+		}
+		return false, stepper.show(stepper.Turn()+count, out)
+	case "g", "goto":
+		if len(args) != 1 {
+			return false, errors.New("usage: g <turn>")
+		}
+		turn, err := strconv.Atoi(args[0])
+		if err != nil {
+			return false, err
+		}
+		return false, stepper.show(turn, out)
+	case "c", "creature":
+		filter, err := optionalInt(args, -1)
+		if err != nil {
+			return false, err
+		}
+		if filter >= 0 && stepper.bot.State.GetCreature(filter) == nil {
+			return false, fmt.Errorf("no creature %d", filter)
+		}
+		stepper.Filter = filter
+		stepper.Print(out)
+	case "r", "rerun":
+		return false, stepper.Rerun(args, out)
+	case "s", "show":
+		stepper.Print(out)
+	case "h", "help":
+		fmt.Fprint(out, stepperHelp)
+	case "q", "quit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q, h for help", fields[0])
+	}
+	return false, nil
+}
+
+// Turn returns the turn shown.
+func (stepper *Stepper) Turn() int {
+	return stepper.bot.State.Turn
+}
+
+// Goto rebuilds the beliefs of the player on the given turn, clamped to the recorded turns.
+func (stepper *Stepper) Goto(turn int) error {
+	turn = clamp(turn, 1, len(stepper.Replay.Turns))
+	if stepper.bot == nil || turn < stepper.Turn() {
+		strategy, err := NewStrategy(stepper.Strategy)
+		if err != nil {
+			return err
+		}
+		if stepper.bot, err = NewReplayBot(stepper.Replay, stepper.Player, strategy); err != nil {
+			return err
+		}
+	}
+	for stepper.Turn() < turn {
+		if err := stepper.bot.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// show goes to the turn and prints it.
+func (stepper *Stepper) show(turn int, out io.Writer) error {
+	if err := stepper.Goto(turn); err != nil {
+		return err
+	}
+	stepper.Print(out)
+	return nil
+}
+
+// Rerun plays the turn shown again with another strategy and logger configuration, printing the logs and
+// the commands. The beliefs up to the previous turn are the ones of the recorded game.
+func (stepper *Stepper) Rerun(args []string, out io.Writer) error {
+	strategyName, spec := stepper.Strategy, "debug"
+	if len(args) > 0 {
+		strategyName = args[0]
+	}
+	if len(args) > 1 {
+		spec = args[1]
+	}
+	strategy, err := NewStrategy(strategyName)
+	if err != nil {
+		return err
+	}
+	configured, err := ParseLogger(spec)
+	if err != nil {
+		return err
+	}
+
+	base, err := NewStrategy(stepper.Strategy)
+	if err != nil {
+		return err
+	}
+	bot, err := NewReplayBot(stepper.Replay, stepper.Player, base)
+	if err != nil {
+		return err
+	}
+	turn := stepper.Turn()
+	for bot.State.Turn < turn-1 {
+		if err := bot.Step(); err != nil {
+			return err
+		}
+	}
+
+	previousLogger, previousWriter := logger, logWriter
+	logger, logWriter = configured, out
+	defer func() { logger, logWriter = previousLogger, previousWriter }()
+	bot.Strategy = strategy
+	if err := bot.Step(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Re-run of turn %d with %s:\n", turn, strategyName)
+	stepper.printDecisions(out, bot)
+	return nil
+}
+
+// Print writes the turn shown: scores, scans, drones, creatures as estimated and in truth, and the decisions.
+func (stepper *Stepper) Print(out io.Writer) {
+	state, turn := stepper.bot.State, stepper.bot.Turn()
+	fmt.Fprintf(out, "Turn %d of %d, %s vs %s\n", turn.Turn, len(stepper.Replay.Turns),
+		stepper.Replay.Result.Names[0], stepper.Replay.Result.Names[1])
+	fmt.Fprintf(out, "Score %d - %d, my saved %v, foe saved %v\n", state.MyScore, state.FoeScore,
+		creatureIds(state.MyScans), creatureIds(state.FoeScans))
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Drone\tSide\tPosition\tBattery\tEmergency\tScans")
+	for _, side := range []struct {
+		name   string
+		drones []*Drone
+	}{{"me", state.MyDrones}, {"foe", state.FoeDrones}} {
+		for _, drone := range side.drones {
+			fmt.Fprintf(table, "%d\t%s\t%.0f,%.0f\t%d\t%t\t%v\n", drone.Id, side.name, drone.Pos.X, drone.Pos.Y,
+				drone.Battery, drone.IsEmergency(), creatureIds(drone.Scans))
+		}
+	}
+	_ = table.Flush()
+
+	table = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Creature\tType\tColor\tEstimate\tSeen\tBounds\tTruth\tError")
+	for _, creature := range state.Creatures {
+		if stepper.Filter >= 0 && creature.Id != stepper.Filter {
+			continue
+		}
+		seen := "no"
+		if creature.LastVisibleTurn == state.Turn {
+			seen = "now"
+		} else if creature.LastVisibleTurn > 0 {
+			seen = fmt.Sprintf("turn %d", creature.LastVisibleTurn)
+		}
+		if creature.Dead {
+			seen = "lost"
+		}
+		bounds := creature.Bounds
+		truth, errorText := "-", "-"
+		if real := turn.creature(creature.Id); real != nil && !real.Lost {
+			truth = fmt.Sprintf("%d,%d", real.X, real.Y)
+			errorText = strconv.Itoa(int(creature.Pos.Dist(V(real.X, real.Y))))
+		}
+		fmt.Fprintf(table, "%d\t%s\t%d\t%.0f,%.0f\t%s\t%d,%d-%d,%d\t%s\t%s\n", creature.Id, creatureTypeName(creature.Type),
+			creature.Color, creature.Pos.X, creature.Pos.Y, seen, bounds.MinX, bounds.MinY, bounds.MaxX, bounds.MaxY, truth, errorText)
+	}
+	_ = table.Flush()
+
+	if stepper.Filter >= 0 {
+		for _, drone := range state.MyDrones {
+			fmt.Fprintf(out, "Drone %d radar: %s\n", drone.Id, drone.RadarBlips[stepper.Filter])
+		}
+	}
+	stepper.printDecisions(out, stepper.bot)
+}
+
+// printDecisions writes the decision and command of each drone of the bot on its last turn.
+func (stepper *Stepper) printDecisions(out io.Writer, bot *ReplayBot) {
+	commands := bot.Commands()
+	for i, drone := range bot.State.MyDrones {
+		if drone.Trace != nil && drone.Trace.Turn == bot.State.Turn {
+			fmt.Fprintln(out, drone.Trace)
+		} else if i < len(commands) {
+			fmt.Fprintf(out, "Drone %d -> %s\n", drone.Id, commands[i])
+		}
+	}
+}
+
+// creatureTypeName returns a short name of the creature type.
+func creatureTypeName(creatureType CreatureType) string {
+	switch creatureType {
+	case Monster:
+		return "monster"
+	case ShallowFish:
+		return "shallow"
+	case MediumFish:
+		return "medium"
+	case DeepFish:
+		return "deep"
+	}
+	return strconv.Itoa(int(creatureType))
+}
+
+// optionalInt parses the only argument if there is one.
+func optionalInt(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	return strconv.Atoi(args[0])
+}
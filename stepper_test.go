@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStepper_NavigatesTurns(t *testing.T) {
+	stepper := &Stepper{Replay: recordedMatch(t), Strategy: "default", Filter: -1}
+	var out strings.Builder
+
+	err := stepper.Run(strings.NewReader("n 10\np 3\ng 5\nc 4\nq\nn\n"), &out)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stepper.Turn() != 5 || stepper.Filter != 4 {
+		t.Errorf("Expected to stop on turn 5 showing creature 4, got turn %d creature %d", stepper.Turn(), stepper.Filter)
+	}
+	text := out.String()
+	for _, expected := range []string{"Turn 11 of", "Turn 8 of", "Drone 0 radar:"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %q in the output", expected)
+		}
+	}
+}
+
+func TestStepper_GoingBackRebuildsTheSameState(t *testing.T) {
+	stepper := &Stepper{Replay: recordedMatch(t), Strategy: "default", Filter: -1}
+	if err := stepper.Goto(20); err != nil {
+		t.Fatal(err)
+	}
+	var forward strings.Builder
+	stepper.Print(&forward)
+
+	if err := stepper.Goto(40); err != nil {
+		t.Fatal(err)
+	}
+	if err := stepper.Goto(20); err != nil {
+		t.Fatal(err)
+	}
+	var back strings.Builder
+	stepper.Print(&back)
+
+	if forward.String() != back.String() {
+		t.Errorf("Expected the same turn 20, got\n%s\nthen\n%s", forward.String(), back.String())
+	}
+}
+
+func TestStepper_RerunWithOtherStrategy(t *testing.T) {
+	stepper := &Stepper{Replay: recordedMatch(t), Strategy: "default", Filter: -1}
+	if err := stepper.Goto(12); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+
+	if err := stepper.Rerun([]string{"idle", "warn"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Re-run of turn 12 with idle:\nDrone 0 -> WAIT 0\nDrone 2 -> WAIT 0\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("Expected the idle commands, got %q", out.String())
+	}
+	if logger.Level != LevelInfo || stepper.Turn() != 12 {
+		t.Errorf("Expected the logger and the turn shown to be restored")
+	}
+	if err := stepper.Rerun([]string{"idle", "loud"}, &out); err == nil {
+		t.Errorf("Expected an invalid log config to be rejected")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Strategy decides the command of every one of my drones for the current turn.
+// Commands are written to state.Out, one line per drone in the order of state.MyDrones.
+type Strategy interface {
+	Name() string
+	Play(state *GameState)
+}
+
+// strategies holds the in-process strategies that can be selected by name, e.g. in the arena.
+var strategies = map[string]func() Strategy{
+	"default": func() Strategy { return NewDefaultStrategy() },
+	"idle":    func() Strategy { return &IdleStrategy{} },
+}
+
+// NewStrategy returns a new strategy registered under the given name.
+func NewStrategy(name string) (Strategy, error) {
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, available: %v", name, StrategyNames())
+	}
+	return factory(), nil
+}
+
+// StrategyNames returns the sorted names of all registered strategies.
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultStrategy lets every drone run its own controller, optionally summarizing each decision in the
+// message of the command.
+type DefaultStrategy struct {
+	Summarize bool
+}
+
+// NewDefaultStrategy returns the strategy used when the bot runs in the game, summarizing decisions when
+// the logger configuration asks for it.
+func NewDefaultStrategy() *DefaultStrategy {
+	return &DefaultStrategy{Summarize: logger.Summarize}
+}
+
+// Name returns the name of the strategy.
+func (strategy *DefaultStrategy) Name() string {
+	return "default"
+}
+
+// Play moves each of my drones with the drone controller and writes its command, logging the whole
+// decision at debug level and only its summary at info level.
+func (strategy *DefaultStrategy) Play(state *GameState) {
+	for _, drone := range state.MyDrones {
+		drone.Move(state)
+		decision := drone.trace(state)
+		logger.Fields(LevelDebug, CategoryDecision, "decision", map[string]any{"trace": decision})
+		logger.Fields(LevelInfo, CategoryDecision, "decision", map[string]any{"drone": drone.Id, "summary": decision.Summary()})
+		fmt.Fprintln(state.Out, decision.Command(strategy.Summarize))
+	}
+}
+
+// IdleStrategy keeps all drones waiting with the light off, a baseline for the arena.
+type IdleStrategy struct{}
+
+// Name returns the name of the strategy.
+func (strategy *IdleStrategy) Name() string {
+	return "idle"
+}
+
+// Play makes every drone wait.
+func (strategy *IdleStrategy) Play(state *GameState) {
+	for range state.MyDrones {
+		fmt.Fprintln(state.Out, "WAIT 0")
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import "math"
+
+const (
+	SurfaceMaxTurns       = 30  // plans longer than this are cut, the drone is stuck between monsters
+	SurfaceDangerLimit    = 0.8 // danger of a step below which the drone keeps to its course
+	SurfacePointsToAscend = 64  // projected score at which all drones deliver
+	SurfaceRaceMinPoints  = 4   // contested points worth racing the foe to the surface for
+)
+
+var surfaceDetourAngles = []float64{0, -30, 30, -60, 60, -90, 90}
+
+// SurfacePlan is the path of a drone to the surface and what it will save on arrival.
+type SurfacePlan struct {
+	DroneId     int
+	Path        []Vec2
+	Turns       int
+	ArrivalTurn int
+	Scans       []*Creature
+	Detour      bool
+}
+
+// PlanSurfacing computes a path to the surface that keeps the drone out of reach of the monsters as they
+// are predicted to move, one drone move per turn, and the turn the carried scans will be saved.
+func (state *GameState) PlanSurfacing(drone *Drone) SurfacePlan {
+	plan := SurfacePlan{DroneId: drone.Id}
+	if drone.IsEmergency() {
+		// Scans are lost and the drone floats up on its own
+		plan.Turns = drone.TurnsUntilRepaired()
+		plan.ArrivalTurn = state.Turn + plan.Turns
+		return plan
+	}
+
+	pos := drone.Pos
+	for turn := 1; pos.Y > SurfaceDepth && turn <= SurfaceMaxTurns; turn++ {
+		best := pos
+		bestDanger := math.Inf(1)
+		for _, angle := range surfaceDetourAngles {
+			next := engineRound(pos.Add(V(0, -1).Rotate(angle).Scale(DroneMovement)).ClampToMap())
+			next.Y = math.Max(SurfaceDepth, next.Y)
+			danger := state.Danger().Segment(pos, next, turn)
+			if danger < SurfaceDangerLimit {
+				best = next
+				plan.Detour = plan.Detour || angle != 0
+				break
+			}
+			if danger < bestDanger {
+				best, bestDanger = next, danger
+			}
+		}
+		pos = best
+		plan.Path = append(plan.Path, pos)
+	}
+
+	plan.Turns = len(plan.Path)
+	plan.ArrivalTurn = state.Turn + plan.Turns
+	for _, scan := range drone.Scans {
+		if !scan.IsDelivered(state) {
+			plan.Scans = append(plan.Scans, scan)
+		}
+	}
+	return plan
+}
+
+// ShouldSurface decides if the drone goes to deliver its scans now and why.
+func (drone *Drone) ShouldSurface(state *GameState, plan SurfacePlan) (bool, string) {
+	if len(plan.Scans) == 0 {
+		return false, "nothing to save"
+	}
+	if state.CalculatePotentialPoints() >= SurfacePointsToAscend {
+		return true, "score threshold"
+	}
+	if state.Turn+plan.Turns+EndgameMargin >= MaxTurns {
+		return true, "turn limit"
+	}
+	if state.IsScoreLocked() {
+		return true, "score locked"
+	}
+
+	// Save first the scans the foe carries too if we can get there before it, any of them as soon as the
+	// foe is seen heading for the surface
+	contested := 0
+	raceMinPoints := SurfaceRaceMinPoints
+	for _, scan := range plan.Scans {
+		if scan.IsDeliveredByFoe(state) {
+			continue
+		}
+		for _, foeDrone := range state.FoeDrones {
+			if containsCreature(foeDrone.Scans, scan.Id) && state.PlanSurfacing(foeDrone).ArrivalTurn >= plan.ArrivalTurn {
+				contested += getScanPoints(scan.Type, true)
+				if state.IsFoeSurfacing(scan) {
+					raceMinPoints = 1
+				}
+				break
+			}
+		}
+	}
+	if contested >= raceMinPoints {
+		return true, "race foe"
+	}
+	return false, "keep scanning"
+}
@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+func TestPlanSurfacing_StraightUp(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.UpdateMyDrone(0, 5000, 8000, 0, 30)
+	drone := state.GetDrone(0)
+	drone.AddScan(state.GetCreature(4))
+
+	plan := state.PlanSurfacing(drone)
+
+	if plan.Turns != 13 || plan.Detour {
+		t.Errorf("Expected 13 turns straight up, got %d turns, detour %t", plan.Turns, plan.Detour)
+	}
+	if len(plan.Scans) != 1 || plan.ArrivalTurn != state.Turn+13 {
+		t.Errorf("Expected creature 4 to be saved on turn %d, got %v on turn %d", state.Turn+13, creatureIds(plan.Scans), plan.ArrivalTurn)
+	}
+}
+
+func TestPlanSurfacing_DetoursAroundMonster(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(16, 5000, 6800, 0, 0)
+	state.UpdateMyDrone(0, 5000, 8000, 0, 30)
+
+	plan := state.PlanSurfacing(state.GetDrone(0))
+
+	if !plan.Detour {
+		t.Errorf("Expected a detour around the monster, got %v", plan.Path)
+	}
+	for _, point := range plan.Path {
+		if point.Dist(V(5000, 6800)) <= MonsterHitRadius {
+			t.Errorf("Expected path to stay away from the monster, got %v", point)
+		}
+	}
+}
+
+func TestShouldSurface_RaceFoeForContestedScans(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	state.AddCreature(NewCreature(5, 1, DeepFish))
+	// A fish left for the foe to catch up with, so the score is not locked
+	state.AddCreature(NewCreature(6, 2, DeepFish))
+	state.UpdateCreature(6, 2000, 8000, 0, 0)
+	state.UpdateMyDrone(0, 5000, 3000, 0, 30)
+	state.UpdateFoeDrone(1, 2000, 6000, 0, 30)
+	for _, id := range []int{0, 1} {
+		state.GetDrone(id).AddScan(state.GetCreature(4))
+		state.GetDrone(id).AddScan(state.GetCreature(5))
+	}
+	drone := state.GetDrone(0)
+
+	surface, reason := drone.ShouldSurface(state, state.PlanSurfacing(drone))
+
+	if !surface || reason != "race foe" {
+		t.Errorf("Expected to race the foe to the surface, got %t %s", surface, reason)
+	}
+}
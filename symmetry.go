@@ -0,0 +1,42 @@
+package main
+
+const (
+	SymmetryDrift = 50 // how far twins drift apart from the mirrored layout per turn of play
+)
+
+// Twin returns the creature created as the mirror image of the given one, nil if it has none. Twins are
+// consecutive ids starting at an even id, of the same type and mirrored colors c and c^1, as every color
+// and type combination exists once.
+func (state *GameState) Twin(creature *Creature) *Creature {
+	if state.twins == nil {
+		state.twins = make(map[int]*Creature)
+		for i := 0; i+1 < len(state.Creatures); i++ {
+			first, second := state.Creatures[i], state.Creatures[i+1]
+			if first.Type == Monster || first.Id%2 != 0 || second.Id != first.Id+1 || second.Color != first.Color^1 || first.Type != second.Type {
+				continue
+			}
+			state.twins[first.Id] = second
+			state.twins[second.Id] = first
+			i++
+		}
+	}
+	return state.twins[creature.Id]
+}
+
+// SymmetryPrior returns the box the creature is expected in from the last sighting of its twin mirrored
+// across the vertical center line, grown by how far both may have moved since.
+func (state *GameState) SymmetryPrior(creature *Creature) (Box, bool) {
+	twin := state.Twin(creature)
+	if twin == nil || twin.LastVisibleTurn == NotInitialized {
+		return Box{}, false
+	}
+	margin := SymmetryDrift*state.Turn + FishSpeed*(state.Turn-twin.LastVisibleTurn)
+	minY, maxY := creatureDepths(creature.Type)
+	x, y := Vec2{MapSize - twin.LastSeen.X, twin.LastSeen.Y}.Ints()
+	return Box{
+		MinX: clamp(x-margin, 0, MapSize),
+		MinY: clamp(y-margin, minY, maxY),
+		MaxX: clamp(x+margin, 0, MapSize),
+		MaxY: clamp(y+margin, minY, maxY),
+	}, true
+}
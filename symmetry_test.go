@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// refereeLayout returns a state initialized with the creatures of the referee layout of the seed.
+func refereeLayout(t *testing.T, seed int64) (*GameState, *Referee) {
+	t.Helper()
+	referee := NewReferee(seed)
+	state := NewGameState()
+	if err := state.ReadInit(strings.NewReader(referee.InitInput(0))); err != nil {
+		t.Fatal(err)
+	}
+	return state, referee
+}
+
+func TestTwin_MirroredPairsOfTheLayout(t *testing.T) {
+	state, referee := refereeLayout(t, 1)
+
+	for _, creature := range referee.Creatures {
+		twin := state.Twin(state.GetCreature(creature.Id))
+		if creature.Type == Monster {
+			if twin != nil {
+				t.Errorf("Expected monster %d to have no twin, got %v", creature.Id, twin)
+			}
+			continue
+		}
+		if twin == nil {
+			t.Errorf("Expected fish %d to have a twin", creature.Id)
+			continue
+		}
+		mirror := referee.creature(twin.Id)
+		if mirror.X != MapSize-creature.X || mirror.Y != creature.Y || twin.Color != creature.Color^1 {
+			t.Errorf("Expected %d and %d to be mirrored, got %d,%d and %d,%d", creature.Id, twin.Id, creature.X, creature.Y, mirror.X, mirror.Y)
+		}
+	}
+}
+
+func TestTwin_NotAcrossPairs(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(5, 1, ShallowFish))
+	state.AddCreature(NewCreature(6, 0, ShallowFish))
+	state.AddCreature(NewCreature(7, 1, MediumFish))
+
+	for _, id := range []int{5, 6, 7} {
+		if twin := state.Twin(state.GetCreature(id)); twin != nil {
+			t.Errorf("Expected %d to have no twin, got %v", id, twin)
+		}
+	}
+}
+
+func TestEstimate_SeededFromTwin(t *testing.T) {
+	state, _ := refereeLayout(t, 1)
+	fish, twin := state.GetCreature(8), state.GetCreature(9)
+	if fish.Type != MediumFish || state.Twin(fish) != twin {
+		t.Fatalf("Expected medium fish 8 and 9 to be twins")
+	}
+	state.UpdateMyDrone(0, 2000, 500, 0, 30)
+	state.UpdateCreature(8, 2500, 6000, 0, 0)
+	state.UpdateRadarBlip(0, 9, string(BottomRight))
+	state.NextTurn()
+
+	state.Estimate(twin)
+
+	if twin.Pos.Dist(V(MapSize-2500, 6000)) > 2*FishSpeed {
+		t.Errorf("Expected estimate close to the mirrored twin, got %v", twin.Pos)
+	}
+	if twin.Bounds.MinX < 2000 {
+		t.Errorf("Expected estimate to still agree with the radar, got %v", twin.Bounds)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Vec2 is a position or a velocity on the map. Values keep their fractions until Ints, which snaps them
+// to the integer grid the way the game does.
+type Vec2 struct {
+	X float64
+	Y float64
+}
+
+// V returns the vector of integer coordinates x,y.
+func V(x, y int) Vec2 {
+	return Vec2{float64(x), float64(y)}
+}
+
+// Add returns v+o.
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns v-o.
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{v.X - o.X, v.Y - o.Y}
+}
+
+// Scale returns v multiplied by factor.
+func (v Vec2) Scale(factor float64) Vec2 {
+	return Vec2{v.X * factor, v.Y * factor}
+}
+
+// Dot returns the dot product of v and o.
+func (v Vec2) Dot(o Vec2) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
+// Length returns the euclidean length of v.
+func (v Vec2) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Dist returns the distance between v and o.
+func (v Vec2) Dist(o Vec2) float64 {
+	return v.Sub(o).Length()
+}
+
+// IsZero returns true for the zero vector.
+func (v Vec2) IsZero() bool {
+	return v.X == 0 && v.Y == 0
+}
+
+// Normalize returns v with length 1, the zero vector stays zero.
+func (v Vec2) Normalize() Vec2 {
+	length := v.Length()
+	if length == 0 {
+		return Vec2{}
+	}
+	return v.Scale(1 / length)
+}
+
+// WithLength returns v scaled to the given length, the zero vector stays zero.
+func (v Vec2) WithLength(length float64) Vec2 {
+	return v.Normalize().Scale(length)
+}
+
+// Truncate returns v shortened to at most the given length.
+func (v Vec2) Truncate(length float64) Vec2 {
+	if v.Length() <= length {
+		return v
+	}
+	return v.WithLength(length)
+}
+
+// Rotate returns v rotated by the given angle in degrees, clockwise on the map as y grows downwards.
+func (v Vec2) Rotate(angleDeg float64) Vec2 {
+	sin, cos := math.Sincos(angleDeg * math.Pi / 180)
+	return Vec2{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
+
+// ClampToMap returns v moved within the map.
+func (v Vec2) ClampToMap() Vec2 {
+	return Vec2{math.Max(0, math.Min(MapSize, v.X)), math.Max(0, math.Min(MapSize, v.Y))}
+}
+
+// ClosestOnSegment returns the point of the segment from a to b closest to v.
+func (v Vec2) ClosestOnSegment(a, b Vec2) Vec2 {
+	ab := b.Sub(a)
+	lengthSq := ab.Dot(ab)
+	if lengthSq == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, v.Sub(a).Dot(ab)/lengthSq))
+	return a.Add(ab.Scale(t))
+}
+
+// Ints returns the coordinates of v snapped to the grid the way the engine does.
+func (v Vec2) Ints() (int, int) {
+	rounded := engineRound(v)
+	return int(rounded.X), int(rounded.Y)
+}
+
+// String returns the coordinates of v.
+func (v Vec2) String() string {
+	return fmt.Sprintf("(%g, %g)", v.X, v.Y)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVec2_ZeroVectorStaysZero(t *testing.T) {
+	if v := (Vec2{}).Normalize(); !v.IsZero() {
+		t.Errorf("Expected zero, got %v", v)
+	}
+	if v := (Vec2{}).WithLength(600); !v.IsZero() {
+		t.Errorf("Expected zero, got %v", v)
+	}
+}
+
+func TestVec2_RotateAndLength(t *testing.T) {
+	v := V(0, -1).Rotate(90).Scale(DroneMovement)
+	if x, y := v.Ints(); x != 600 || y != 0 {
+		t.Errorf("Expected 600,0, got %d,%d", x, y)
+	}
+	if length := V(300, 400).Length(); length != 500 {
+		t.Errorf("Expected 500, got %f", length)
+	}
+	if v := V(3000, 4000).Truncate(DroneMovement); math.Abs(v.Length()-DroneMovement) > 1e-9 {
+		t.Errorf("Expected length 600, got %f", v.Length())
+	}
+}
+
+func TestVec2_RoundHalvesUp(t *testing.T) {
+	if x, y := (Vec2{2.5, -2.5}).Ints(); x != 3 || y != -2 {
+		t.Errorf("Expected 3,-2, got %d,%d", x, y)
+	}
+}
+
+func TestVec2_ClampAndClosestOnSegment(t *testing.T) {
+	if v := (Vec2{-10, 12000}).ClampToMap(); v != V(0, MapSize) {
+		t.Errorf("Expected 0,%d, got %v", MapSize, v)
+	}
+	if p := V(500, 300).ClosestOnSegment(V(0, 0), V(1000, 0)); p != V(500, 0) {
+		t.Errorf("Expected 500,0, got %v", p)
+	}
+	if p := V(-500, 300).ClosestOnSegment(V(0, 0), V(1000, 0)); p != V(0, 0) {
+		t.Errorf("Expected the segment start, got %v", p)
+	}
+}
+
+func TestGetNextPositionTowardsTarget_OnTarget(t *testing.T) {
+	drone := &Drone{Pos: V(5000, 5000)}
+	if next := drone.GetNextPositionTowardsTarget(V(5000, 5000)); next != V(5000, 5000) {
+		t.Errorf("Expected to stay at 5000,5000, got %v", next)
+	}
+	if next := drone.GetNextPositionTowardsTarget(V(5000, 8000)); next != V(5000, 5600) {
+		t.Errorf("Expected 5000,5600, got %v", next)
+	}
+}
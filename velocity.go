@@ -0,0 +1,122 @@
+package main
+
+import "math"
+
+// UncertaintyGrowth is how much the box of a creature out of sight grows each predicted turn.
+const UncertaintyGrowth = 100
+
+// PredictVelocity returns the velocity of the creature for its next move the way the engine updates it
+// after a move: fish flee from the closest drone they hear, swim away from a fish too close or keep
+// swimming at their normal speed, monsters chase the closest drone whose light reaches them, swim away
+// from a monster too close or go back to their idle speed, then all bounce off the habitat edges. Fish
+// never seen keep a zero velocity.
+func (creature *Creature) PredictVelocity(state *GameState) Vec2 {
+	speed := creature.Speed
+	if creature.Type == Monster {
+		return BounceSpeed(creature.Type, creature.Pos, creature.monsterSpeed(state), false)
+	}
+	if speed.IsZero() {
+		return Vec2{}
+	}
+
+	if drone, dist := state.closestActiveDrone(creature.Pos); drone != nil && dist <= FishHearingRadius {
+		speed = CreatureSpeed(creature.Pos.Sub(drone.MovedPos()), FishFleeSpeed)
+		return BounceSpeed(creature.Type, creature.Pos, speed, true)
+	}
+	speed = CreatureSpeed(speed, FishSpeed)
+	for _, fish := range state.Creatures {
+		if fish.Type == Monster || fish.Dead || fish.Id == creature.Id {
+			continue
+		}
+		if creature.Pos.Dist(fish.Pos) <= FishCollision {
+			speed = CreatureSpeed(creature.Pos.Sub(fish.Pos), FishSpeed)
+			break
+		}
+	}
+	return BounceSpeed(creature.Type, creature.Pos, speed, false)
+}
+
+// monsterSpeed returns the speed of a monster before bouncing: it chases the closest drone whose light
+// reaches it, otherwise swims away from a monster too close or keeps its heading at idle speed.
+func (creature *Creature) monsterSpeed(state *GameState) Vec2 {
+	if drone := state.closestLitDrone(creature.Pos); drone != nil {
+		return CreatureSpeed(drone.MovedPos().Sub(creature.Pos), MonsterAttackSpeed)
+	}
+	for _, monster := range state.GetMonsters() {
+		if monster.Dead || monster.Id == creature.Id {
+			continue
+		}
+		if creature.Pos.Dist(monster.Pos) <= FishCollision {
+			return CreatureSpeed(creature.Pos.Sub(monster.Pos), MonsterSpeed)
+		}
+	}
+	return CreatureSpeed(creature.Speed, MonsterSpeed)
+}
+
+// closestLitDrone returns the closest drone not in emergency whose light reaches pos, nil if there is none.
+func (state *GameState) closestLitDrone(pos Vec2) *Drone {
+	var closest *Drone
+	closestDistance := math.Inf(1)
+	for _, drones := range [][]*Drone{state.MyDrones, state.FoeDrones} {
+		for _, drone := range drones {
+			if drone.IsEmergency() {
+				continue
+			}
+			dist := pos.Dist(drone.MovedPos())
+			if dist <= float64(state.lightRadius(drone)) && dist < closestDistance {
+				closest, closestDistance = drone, dist
+			}
+		}
+	}
+	return closest
+}
+
+// MovedPos returns where the drone ends the turn being played, the engine updates speeds after drones
+// moved. Only my drones are known to move, foe drones are assumed to stay.
+func (drone *Drone) MovedPos() Vec2 {
+	if drone.Next != nil {
+		return *drone.Next
+	}
+	return drone.Pos
+}
+
+// lightRadius returns how far the light of the drone reaches on the move being played. My drones light
+// when told to this turn, foe drones are assumed to light as their battery showed on their last move.
+func (state *GameState) lightRadius(drone *Drone) int {
+	lit := drone.LastLightTurn == state.Turn
+	if record, ok := drone.LastBehavior(); ok {
+		lit = record.LightUsed
+	}
+	if lit {
+		return LightScanRadius
+	}
+	return ScanRadius
+}
+
+// closestActiveDrone returns the closest drone of either player to pos that is not in emergency.
+func (state *GameState) closestActiveDrone(pos Vec2) (*Drone, float64) {
+	var closest *Drone
+	closestDistance := math.Inf(1)
+	for _, drones := range [][]*Drone{state.MyDrones, state.FoeDrones} {
+		for _, drone := range drones {
+			if drone.IsEmergency() {
+				continue
+			}
+			if dist := pos.Dist(drone.MovedPos()); dist < closestDistance {
+				closest, closestDistance = drone, dist
+			}
+		}
+	}
+	return closest, closestDistance
+}
+
+// Moved returns the box shifted by speed and grown by margin on every side, within the given depths.
+func (box Box) Moved(speed Vec2, margin, minY, maxY int) Box {
+	vx, vy := speed.Ints()
+	return Box{
+		MinX: clamp(box.MinX+vx-margin, 0, MapSize),
+		MinY: clamp(box.MinY+vy-margin, minY, maxY),
+		MaxX: clamp(box.MaxX+vx+margin, 0, MapSize),
+		MaxY: clamp(box.MaxY+vy+margin, minY, maxY),
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUpdateCreature_KeepsReportedVelocity(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateCreature(4, 5000, 4000, 200, 0)
+	state.NextTurn()
+	state.UpdateCreature(4, 5200, 4000, -141, 141)
+
+	c := state.GetCreature(4)
+	if c.Speed != V(-141, 141) {
+		t.Errorf("Expected the reported velocity -141,141, got %v", c.Speed)
+	}
+}
+
+func TestPredictVelocity_BouncesOffHabitat(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, ShallowFish))
+	state.UpdateCreature(4, 9900, 4900, 120, 160)
+
+	speed := state.GetCreature(4).PredictVelocity(state)
+	if speed != V(-120, -160) {
+		t.Errorf("Expected bounce to -120,-160, got %v", speed)
+	}
+}
+
+func TestPredictVelocity_AvoidsOtherFish(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, MediumFish))
+	state.AddCreature(NewCreature(5, 1, MediumFish))
+	state.UpdateCreature(4, 5000, 6000, 200, 0)
+	state.UpdateCreature(5, 5600, 6000, 0, 0)
+
+	speed := state.GetCreature(4).PredictVelocity(state)
+	if speed.X >= 0 || speed.Y != 0 {
+		t.Errorf("Expected to swim away to the left, got %v", speed)
+	}
+}
+
+func TestMove_GrowsBoundsOutOfSight(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, MediumFish))
+	state.UpdateCreature(4, 5000, 6000, 200, 0)
+
+	c := state.GetCreature(4)
+	c.Move(state)
+	want := Box{5200 - UncertaintyGrowth, 6000 - UncertaintyGrowth, 5200 + UncertaintyGrowth, 6000 + UncertaintyGrowth}
+	if c.Pos != V(5200, 6000) || c.Bounds != want {
+		t.Errorf("Expected 5200,6000 within %v, got %v within %v", want, c.Pos, c.Bounds)
+	}
+}
+
+func TestPredictVelocity_MonsterChasesWhereTheLitDroneEnds(t *testing.T) {
+	state := NewGameState()
+	state.NextTurn()
+	state.AddCreature(NewCreature(16, -1, Monster))
+	state.UpdateCreature(16, 5000, 7000, 270, 0)
+	state.UpdateMyDrone(0, 5000, 5500, 0, 30)
+	drone := state.GetDrone(0)
+	drone.Next = &Vec2{5000, 6100}
+
+	monster := state.GetCreature(16)
+	if speed := monster.PredictVelocity(state); speed != V(270, 0) {
+		t.Errorf("Expected the monster to keep its idle speed out of the dark radius, got %v", speed)
+	}
+	drone.LastLightTurn = state.Turn
+	if speed := monster.PredictVelocity(state); speed != V(0, -MonsterAttackSpeed) {
+		t.Errorf("Expected the monster to chase the lit drone, got %v", speed)
+	}
+}
+
+func TestMoveAll_GrowsBoundsEachTurnOutOfSight(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, MediumFish))
+	state.AddCreature(NewCreature(6, 1, MediumFish))
+	state.UpdateCreature(4, 5000, 6000, 200, 0)
+	state.UpdateCreature(6, 2000, 7400, 0, 0)
+
+	for turn := 1; turn <= 3; turn++ {
+		state.MoveAll()
+		state.Turn++
+	}
+
+	growth := 3 * UncertaintyGrowth
+	swimming := state.GetCreature(4)
+	want := Box{5600 - growth, 6000 - growth, 5600 + growth, 6000 + growth}
+	if swimming.Pos != V(5600, 6000) || swimming.Bounds != want {
+		t.Errorf("Expected 5600,6000 within %v, got %v within %v", want, swimming.Pos, swimming.Bounds)
+	}
+	// The box of a fish at the bottom of its habitat grows within the habitat only
+	resting := state.GetCreature(6)
+	want = Box{2000 - growth, 7400 - growth, 2000 + growth, MediumFishMaxDepth}
+	if resting.Pos != V(2000, 7400) || resting.Bounds != want {
+		t.Errorf("Expected 2000,7400 within %v, got %v within %v", want, resting.Pos, resting.Bounds)
+	}
+}
+
+// The engine moves a creature by the velocity it reports and only then changes it, so the reported
+// velocity is where the creature shows up next. Blending it with earlier sightings, as a smoothed
+// estimate would, lags behind every flee, collision and bounce.
+func TestReportedVelocity_PredictsBetterThanSmoothed(t *testing.T) {
+	const smoothing = 0.7 // weight of the latest sighting in a smoothed velocity
+
+	type sighting struct{ Pos, Speed Vec2 }
+	referee := NewReferee(benchSeed)
+	var previous, latest map[int]sighting
+	var rawError, smoothedError float64
+	predictions := 0
+	for turn := 0; turn < 100 && !referee.Over(); turn++ {
+		// What the referee reports of every creature still in the game
+		seen := map[int]sighting{}
+		for _, creature := range referee.Creatures {
+			if creature.Lost {
+				continue
+			}
+			pos := V(creature.X, creature.Y)
+			seen[creature.Id] = sighting{pos, V(creature.Vx, creature.Vy)}
+			last, ok := latest[creature.Id]
+			before, okBefore := previous[creature.Id]
+			if !ok || !okBefore {
+				continue
+			}
+			smoothed := last.Speed.Scale(smoothing).Add(before.Speed.Scale(1 - smoothing))
+			rawError += pos.Dist(last.Pos.Add(last.Speed))
+			smoothedError += pos.Dist(last.Pos.Add(smoothed))
+			predictions++
+		}
+		previous, latest = latest, seen
+
+		// Dive and surface again to scare fish on the way
+		depth := MapMax
+		if turn%40 >= 20 {
+			depth = 0
+		}
+		var commands [2][]string
+		for p, player := range referee.Players {
+			for _, drone := range player.Drones {
+				commands[p] = append(commands[p], fmt.Sprintf("MOVE %d %d 0", drone.X, depth))
+			}
+		}
+		referee.Step(commands)
+	}
+
+	if predictions < 500 {
+		t.Fatalf("Expected creatures reported on three turns in a row at least 500 times, got %d", predictions)
+	}
+	if rawError >= smoothedError {
+		t.Errorf("Expected the reported velocity to predict better than the smoothed one, got %.0f against %.0f over %d sightings",
+			rawError, smoothedError, predictions)
+	}
+}
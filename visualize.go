@@ -0,0 +1,348 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	FrameSize      = 720 // width and height of a frame in pixels
+	BlipLength     = 400 // length of the radar blip marks drawn from a drone
+	CreatureRadius = 90
+)
+
+var (
+	creatureColors = []string{"#ff5fa2", "#ffd23f", "#4cd964", "#3fa9ff"}
+	habitatFills   = map[CreatureType]string{ShallowFish: "#0d4a7a", MediumFish: "#0a3a63", DeepFish: "#072a4a"}
+	playerColors   = [2]string{"#f5f5f5", "#ff9f40"}
+)
+
+// runVisualize renders a recorded game as one SVG frame per turn, in a single HTML page with a turn slider
+// or as separate SVG files.
+func runVisualize(args []string) int {
+	flags := flag.NewFlagSet("visualize", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: visualize [flags] <replay.json>")
+		fmt.Fprintln(flags.Output(), "Strategies:", strings.Join(StrategyNames(), ", "))
+		flags.PrintDefaults()
+	}
+	player := flags.Int("player", 0, "player whose beliefs are shown")
+	strategyName := flags.String("strategy", "default", "strategy rebuilding the beliefs of the player")
+	output := flags.String("o", "", "HTML page to write, defaults to the replay name with .html")
+	svgDir := flags.String("svg", "", "directory to write one SVG file per turn to instead of the HTML page")
+	verbose := flags.Bool("v", false, "show the logs of the rebuilt bot")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *player < 0 || *player > 1 {
+		flags.Usage()
+		return 2
+	}
+	if !*verbose {
+		logWriter = io.Discard
+		defer func() { logWriter = os.Stderr }()
+	}
+
+	path := flags.Arg(0)
+	frames, err := renderReplay(path, *player, *strategyName)
+	if err != nil {
+		logger.Warn(CategoryTool, err)
+		return 1
+	}
+
+	if *svgDir != "" {
+		err = writeFrames(*svgDir, frames)
+	} else {
+		if *output == "" {
+			*output = strings.TrimSuffix(path, filepath.Ext(path)) + ".html"
+		}
+		err = writePage(*output, filepath.Base(path), frames)
+	}
+	if err != nil {
+		logger.Warn(CategoryTool, err)
+		return 1
+	}
+	return 0
+}
+
+// Frame is the rendering of one turn: the SVG picture and the decisions of the player's drones.
+type Frame struct {
+	Turn      int
+	SVG       string
+	Decisions []string
+}
+
+// renderReplay rebuilds the beliefs of the player with the strategy and renders every recorded turn.
+func renderReplay(path string, player int, strategyName string) ([]Frame, error) {
+	replay, err := LoadReplay(path)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := NewStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+	bot, err := NewReplayBot(replay, player, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []Frame
+	for {
+		if err := bot.Step(); errors.Is(err, io.EOF) {
+			return frames, nil
+		} else if err != nil {
+			return nil, err
+		}
+		frames = append(frames, RenderFrame(bot))
+	}
+}
+
+// RenderFrame draws the truth of the turn last played by the bot together with what the bot believed:
+// habitats, creatures and their estimates with uncertainty boxes, radar blips, monster danger, drones with
+// their scan radius and planned paths.
+func RenderFrame(bot *ReplayBot) Frame {
+	turn, state := bot.Turn(), bot.State
+	frame := Frame{Turn: turn.Turn}
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		FrameSize, FrameSize, MapSize, MapSize)
+
+	// Habitats, surface and the zone monsters cannot leave
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#0f5c96"/>`+"\n", MapSize, MapSize)
+	for _, creatureType := range []CreatureType{ShallowFish, MediumFish, DeepFish} {
+		depths := fishDepthsByType[creatureType]
+		fmt.Fprintf(&svg, `<rect y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+			depths[0], MapSize, depths[1]-depths[0], habitatFills[creatureType])
+	}
+	fmt.Fprintf(&svg, `<line x1="0" y1="%d" x2="%d" y2="%d" stroke="#9fd3ff" stroke-width="20"/>`+"\n", SurfaceDepth, MapSize, SurfaceDepth)
+
+	// Monster danger as the bot believed it, the hit radius and where the danger fades out
+	for _, monster := range state.GetMonsters() {
+		if monster.Dead {
+			continue
+		}
+		fmt.Fprintf(&svg, `<circle cx="%.0f" cy="%.0f" r="%d" fill="#e33" fill-opacity="0.15" stroke="#e33" stroke-width="15"/>`+"\n",
+			monster.Pos.X, monster.Pos.Y, MonsterHitRadius)
+		fmt.Fprintf(&svg, `<circle cx="%.0f" cy="%.0f" r="%d" fill="none" stroke="#e33" stroke-width="10" stroke-dasharray="60 60"/>`+"\n",
+			monster.Pos.X, monster.Pos.Y, MonsterHitRadius+DangerMargin)
+	}
+
+	// Estimates of creatures out of sight, with the box they are known to be in and a line to the truth
+	for _, creature := range state.Creatures {
+		if creature.Dead || creature.LastVisibleTurn == state.Turn {
+			continue
+		}
+		color := creatureColor(creature.Color)
+		bounds := creature.Bounds
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="0.06" stroke="%s" stroke-width="10" stroke-dasharray="40 40"/>`+"\n",
+			bounds.MinX, bounds.MinY, bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY, color, color)
+		fmt.Fprintf(&svg, `<circle cx="%.0f" cy="%.0f" r="%d" fill="none" stroke="%s" stroke-width="20"/>`+"\n",
+			creature.Pos.X, creature.Pos.Y, CreatureRadius, color)
+		if truth := turn.creature(creature.Id); truth != nil && !truth.Lost {
+			fmt.Fprintf(&svg, `<line x1="%.0f" y1="%.0f" x2="%d" y2="%d" stroke="%s" stroke-width="8" stroke-opacity="0.6"/>`+"\n",
+				creature.Pos.X, creature.Pos.Y, truth.X, truth.Y, color)
+		}
+	}
+
+	// True creatures
+	for _, creature := range turn.Creatures {
+		if creature.Lost {
+			continue
+		}
+		radius, color := CreatureRadius, creatureColor(creature.Color)
+		if creature.Type == Monster {
+			radius = 2 * CreatureRadius
+		}
+		fmt.Fprintf(&svg, `<circle cx="%d" cy="%d" r="%d" fill="%s"><title>%d</title></circle>`+"\n",
+			creature.X, creature.Y, radius, color, creature.Id)
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="140" fill="#fff">%d</text>`+"\n",
+			creature.X+radius, creature.Y-radius, creature.Id)
+	}
+
+	// Radar blips of the player's drones, a short mark towards the quadrant of each creature
+	for _, drone := range state.MyDrones {
+		ids := make([]int, 0, len(drone.RadarBlips))
+		for id := range drone.RadarBlips {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			end := drone.Pos.Add(blipDirection(drone.RadarBlips[id]).Scale(BlipLength))
+			color := creatureColor(-1)
+			if creature := state.GetCreature(id); creature != nil {
+				color = creatureColor(creature.Color)
+			}
+			fmt.Fprintf(&svg, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="%s" stroke-width="12" stroke-opacity="0.7"/>`+"\n",
+				drone.Pos.X, drone.Pos.Y, end.X, end.Y, color)
+		}
+	}
+
+	// Drones with the radius they scan this turn, and the planned path of the player's drones
+	for p, drones := range turn.Drones {
+		for i, drone := range drones {
+			light := drone.Light
+			if p == bot.Player && i < len(turn.Commands[p]) {
+				light = commandLight(turn.Commands[p][i])
+			}
+			radius := ScanRadius
+			if light {
+				radius = LightScanRadius
+			}
+			fmt.Fprintf(&svg, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="%s" stroke-width="10" stroke-opacity="0.5"/>`+"\n",
+				drone.X, drone.Y, radius, playerColors[p])
+			fill := playerColors[p]
+			if drone.Emergency {
+				fill = "#888"
+			}
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="200" height="200" fill="%s"><title>drone %d</title></rect>`+"\n",
+				drone.X-100, drone.Y-100, fill, drone.Id)
+		}
+	}
+	for _, drone := range state.MyDrones {
+		if drone.Trace == nil || drone.Trace.Turn != state.Turn {
+			continue
+		}
+		points := []string{fmt.Sprintf("%.0f,%.0f", drone.Pos.X, drone.Pos.Y)}
+		for _, point := range drone.Trace.Path {
+			points = append(points, fmt.Sprintf("%.0f,%.0f", point.X, point.Y))
+		}
+		if x, y, ok := commandTarget(drone.Trace.Action); ok && len(drone.Trace.Path) == 0 {
+			points = append(points, fmt.Sprintf("%d,%d", x, y))
+		}
+		fmt.Fprintf(&svg, `<polyline points="%s" fill="none" stroke="%s" stroke-width="25"/>`+"\n",
+			strings.Join(points, " "), playerColors[bot.Player])
+		frame.Decisions = append(frame.Decisions, drone.Trace.String())
+	}
+
+	fmt.Fprintf(&svg, `<text x="150" y="350" font-size="250" fill="#fff">Turn %d  %d - %d</text>`+"\n",
+		turn.Turn, turn.Scores[0], turn.Scores[1])
+	svg.WriteString("</svg>\n")
+	frame.SVG = svg.String()
+	return frame
+}
+
+// creature returns the truth of the creature with the given id on the turn.
+func (turn *ReplayTurn) creature(id int) *refCreature {
+	for i := range turn.Creatures {
+		if turn.Creatures[i].Id == id {
+			return &turn.Creatures[i]
+		}
+	}
+	return nil
+}
+
+// creatureColor returns the fill of a creature color, monsters having none.
+func creatureColor(color int) string {
+	if color < 0 || color >= len(creatureColors) {
+		return "#e33"
+	}
+	return creatureColors[color]
+}
+
+// blipDirection returns the unit diagonal pointing into the quadrant of the blip.
+func blipDirection(blip RadarBlip) Vec2 {
+	direction := Vec2{X: 1, Y: 1}
+	if blip == TopLeft || blip == TopRight {
+		direction.Y = -1
+	}
+	if blip == TopLeft || blip == BottomLeft {
+		direction.X = -1
+	}
+	return direction.Normalize()
+}
+
+// commandLight returns true if the command switches the light on.
+func commandLight(command string) bool {
+	fields := strings.Fields(command)
+	switch {
+	case len(fields) >= 4 && fields[0] == "MOVE":
+		return fields[3] == "1"
+	case len(fields) >= 2 && fields[0] == "WAIT":
+		return fields[1] == "1"
+	}
+	return false
+}
+
+// commandTarget returns the point a MOVE command heads to.
+func commandTarget(command string) (int, int, bool) {
+	var x, y int
+	if _, err := fmt.Sscanf(command, "MOVE %d %d", &x, &y); err != nil {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// writeFrames writes every frame as its own SVG file.
+func writeFrames(dir string, frames []Frame) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, frame := range frames {
+		name := filepath.Join(dir, fmt.Sprintf("turn%03d.svg", frame.Turn))
+		if err := os.WriteFile(name, []byte(frame.SVG), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePage writes a self-contained HTML page showing one frame at a time with a turn slider.
+func writePage(path, title string, frames []Frame) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WritePage(file, title, frames); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WritePage writes the HTML page of the frames.
+func WritePage(w io.Writer, title string, frames []Frame) error {
+	var page strings.Builder
+	fmt.Fprintf(&page, `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>%s</title>
+<style>body{background:#111;color:#ddd;font-family:monospace} .frame{display:none} .frame.on{display:flex;gap:16px} pre{white-space:pre-wrap;max-width:600px}</style>
+</head><body>
+<h3>%s</h3>
+<input id="turn" type="range" min="0" max="%d" value="0" style="width:%dpx">
+<button onclick="show(current-1)">&lt;</button><button onclick="show(current+1)">&gt;</button>
+<span id="label"></span>
+`, html.EscapeString(title), html.EscapeString(title), max(0, len(frames)-1), FrameSize)
+	for i, frame := range frames {
+		fmt.Fprintf(&page, `<div class="frame" id="frame%d" data-turn="%d">%s<pre>%s</pre></div>`+"\n",
+			i, frame.Turn, frame.SVG, html.EscapeString(strings.Join(frame.Decisions, "\n")))
+	}
+	page.WriteString(`<script>
+var current = 0, slider = document.getElementById("turn");
+function show(i) {
+	var frame = document.getElementById("frame" + i);
+	if (!frame) return;
+	document.getElementById("frame" + current).classList.remove("on");
+	frame.classList.add("on");
+	current = i;
+	slider.value = i;
+	document.getElementById("label").textContent = "turn " + frame.dataset.turn;
+}
+slider.oninput = function() { show(+slider.value); };
+document.onkeydown = function(e) {
+	if (e.key == "ArrowLeft") show(current - 1);
+	if (e.key == "ArrowRight") show(current + 1);
+};
+show(0);
+</script>
+</body></html>
+`)
+	_, err := io.WriteString(w, page.String())
+	return err
+}
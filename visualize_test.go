@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFrame_DrawsTruthAndBeliefs(t *testing.T) {
+	replay := recordedMatch(t)
+	bot, err := NewReplayBot(replay, 0, NewDefaultStrategy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := bot.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	frame := RenderFrame(bot)
+
+	if frame.Turn != 10 || !strings.HasPrefix(frame.SVG, "<svg") || !strings.HasSuffix(frame.SVG, "</svg>\n") {
+		t.Fatalf("Expected an SVG of turn 10, got turn %d", frame.Turn)
+	}
+	if count := strings.Count(frame.SVG, "<title>drone"); count != 4 {
+		t.Errorf("Expected all 4 drones, got %d", count)
+	}
+	if count := strings.Count(frame.SVG, "<polyline"); count != 2 {
+		t.Errorf("Expected a planned move for each of my drones, got %d", count)
+	}
+	if len(frame.Decisions) != 2 || !strings.HasPrefix(frame.Decisions[0], "Drone 0") {
+		t.Errorf("Expected the decisions of my drones, got %q", frame.Decisions)
+	}
+}
+
+func TestWritePage_OneFramePerTurn(t *testing.T) {
+	frames := []Frame{{Turn: 1, SVG: "<svg></svg>"}, {Turn: 2, SVG: "<svg></svg>", Decisions: []string{"Drone 0 <target>"}}}
+	var page strings.Builder
+
+	if err := WritePage(&page, "game", frames); err != nil {
+		t.Fatal(err)
+	}
+
+	text := page.String()
+	if !strings.Contains(text, `id="frame1" data-turn="2"`) || !strings.Contains(text, `max="1"`) {
+		t.Errorf("Expected a slider over both frames, got %s", text)
+	}
+	if !strings.Contains(text, "Drone 0 &lt;target&gt;") {
+		t.Errorf("Expected decisions to be escaped")
+	}
+}
+
+func TestCommandParsing(t *testing.T) {
+	if !commandLight("MOVE 1 2 1 msg") || commandLight("MOVE 1 2 0") || !commandLight("WAIT 1") || commandLight("WAIT 0") {
+		t.Errorf("Expected light to follow the command")
+	}
+	if x, y, ok := commandTarget("MOVE 100 200 0 go"); !ok || x != 100 || y != 200 {
+		t.Errorf("Expected 100,200, got %d,%d", x, y)
+	}
+	if _, _, ok := commandTarget("WAIT 1"); ok {
+		t.Errorf("Expected no target for WAIT")
+	}
+	if direction := blipDirection(TopLeft); direction.X >= 0 || direction.Y >= 0 {
+		t.Errorf("Expected top left to point up and left, got %v", direction)
+	}
+}
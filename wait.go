@@ -0,0 +1,63 @@
+package main
+
+import "fmt"
+
+const (
+	RechargeDepth = ShallowFishMinDepth - ScanRadius // depth above which no fish can be scanned, waiting there only costs time
+	WaitTolerance = 100                              // how far from the approach point sinking may end
+)
+
+// DroneOutcome is where a drone ends its turn and the battery it is left with after a command.
+type DroneOutcome struct {
+	Pos     Vec2
+	Battery int
+	Light   bool
+}
+
+// SimulateMove returns the outcome of MOVE towards target with the given light for the drone.
+func (drone *Drone) SimulateMove(target Vec2, light bool) DroneOutcome {
+	return drone.outcome(DroneMoveTo(drone.Pos, target), light)
+}
+
+// SimulateWait returns the outcome of WAIT light for the drone, it sinks instead of moving.
+func (drone *Drone) SimulateWait(light bool) DroneOutcome {
+	return drone.outcome(DroneWaitAt(drone.Pos), light)
+}
+
+// outcome applies the battery rules of the engine: lighting costs battery if there is enough of it,
+// otherwise the battery recharges.
+func (drone *Drone) outcome(pos Vec2, light bool) DroneOutcome {
+	if drone.IsEmergency() {
+		return DroneOutcome{Pos: DroneEmergencyAt(drone.Pos), Battery: min(DroneMaxBattery, drone.Battery+DroneBatteryRegen)}
+	}
+	if light && drone.Battery >= LightBatteryCost {
+		return DroneOutcome{Pos: pos, Battery: drone.Battery - LightBatteryCost, Light: true}
+	}
+	return DroneOutcome{Pos: pos, Battery: min(DroneMaxBattery, drone.Battery+DroneBatteryRegen)}
+}
+
+// ShouldWait decides if waiting serves the drone better than moving to end on its way to the
+// approach point: sinking gets as close when the approach point is straight below, and above the fish
+// habitats a drone without the battery to light once recharges while sinking towards them.
+func (drone *Drone) ShouldWait(state *GameState, end, approach Vec2) (bool, string) {
+	if drone.IsEmergency() {
+		return false, "emergency"
+	}
+	wait := engineRound(drone.SimulateWait(false).Pos)
+	if approach.Y <= drone.Pos.Y {
+		return false, "target above"
+	}
+	// Only when the move would end where sinking does, a sweep or detour is worth more than the WAIT
+	if end == drone.GetNextPositionTowardsTarget(approach) && wait.Dist(approach) <= WaitTolerance {
+		return true, "sinking onto target"
+	}
+	if wait.Y <= RechargeDepth && !state.IsEndgame() && drone.Battery < min(LightBatteryCost, drone.BatteryReserve(state)) {
+		return true, fmt.Sprintf("recharging %d/%d", drone.Battery, drone.BatteryReserve(state))
+	}
+	return false, "moving"
+}
+
+// String returns a short representation of the outcome for logging.
+func (outcome DroneOutcome) String() string {
+	return fmt.Sprintf("DroneOutcome{Pos: %v, Battery: %d, Light: %t}", outcome.Pos, outcome.Battery, outcome.Light)
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestSimulateWait_SinksAndRecharges(t *testing.T) {
+	drone := &Drone{Pos: V(5000, 3000), Battery: 10}
+
+	if outcome := drone.SimulateWait(false); outcome.Pos != V(5000, 3300) || outcome.Battery != 11 || outcome.Light {
+		t.Errorf("Expected to sink to 5000,3300 with 11 battery, got %v", outcome)
+	}
+	if outcome := drone.SimulateWait(true); outcome.Battery != 5 || !outcome.Light {
+		t.Errorf("Expected lighting to cost 5 battery, got %v", outcome)
+	}
+	drone.Battery = 3
+	if outcome := drone.SimulateMove(V(5000, 5000), true); outcome.Pos != V(5000, 3600) || outcome.Battery != 4 || outcome.Light {
+		t.Errorf("Expected no light without battery, got %v", outcome)
+	}
+}
+
+func TestShouldWait_SinkOntoTargetBelow(t *testing.T) {
+	state := NewGameState()
+	drone := &Drone{Pos: V(5000, 3000), Battery: 30}
+
+	if wait, reason := drone.ShouldWait(state, V(5000, 3250), V(5000, 3250)); !wait {
+		t.Errorf("Expected to sink onto the target straight below, got %s", reason)
+	}
+	if wait, reason := drone.ShouldWait(state, V(5400, 3400), V(5800, 3800)); wait {
+		t.Errorf("Expected to move towards a target aside, got %s", reason)
+	}
+}
+
+func TestShouldWait_RechargeAboveHabitats(t *testing.T) {
+	state := NewGameState()
+	state.AddCreature(NewCreature(4, 0, DeepFish))
+	drone := &Drone{Pos: V(5000, SurfaceDepth), Battery: 2}
+
+	if wait, reason := drone.ShouldWait(state, V(5000, 1100), V(5000, 8000)); !wait {
+		t.Errorf("Expected to recharge above the habitats, got %s", reason)
+	}
+	drone.Pos.Y = 3000
+	if wait, reason := drone.ShouldWait(state, V(5000, 3600), V(5000, 8000)); wait {
+		t.Errorf("Expected to move once within the habitats, got %s", reason)
+	}
+}